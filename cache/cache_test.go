@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store, err := Open(t.TempDir())
+	assert.NoError(t, err)
+
+	key := Key("https://api.github.com/users/octocat", "token")
+	assert.NotEqual(t, key, Key("https://api.github.com/users/octocat", ""))
+
+	_, ok := store.Get(key)
+	assert.False(t, ok)
+
+	stored := time.Now().Truncate(time.Second)
+	err = store.Put(key, &Entry{URL: "https://api.github.com/users/octocat", Body: []byte(`{"login":"octocat"}`), ETag: `"abc"`, StoredAt: stored})
+	assert.NoError(t, err)
+
+	entry, ok := store.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, `{"login":"octocat"}`, string(entry.Body))
+	assert.Equal(t, `"abc"`, entry.ETag)
+	assert.True(t, stored.Equal(entry.StoredAt))
+
+	stats, err := store.Stats()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Positive(t, stats.Size)
+
+	assert.NoError(t, store.Clear())
+	_, ok = store.Get(key)
+	assert.False(t, ok)
+	stats, err = store.Stats()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.Entries)
+}
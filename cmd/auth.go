@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"ghexplorer/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	authCmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the stored forge credentials",
+		Long: `Store, inspect or remove the personal access token used to authenticate
+against the forge API. A token from --token or the forge environment variables
+(GITHUB_TOKEN, GH_TOKEN, GITEA_TOKEN, GITLAB_TOKEN) takes precedence over the
+stored credential. Credentials are stored per forge, as selected by --forge.`,
+	}
+
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Store a personal access token read from standard input",
+		Long: `Read a personal access token from standard input and store it for later use.
+
+Example:
+  echo "$TOKEN" | ghexplorer auth login`,
+		Args: cobra.NoArgs,
+		Run:  runAuthLogin,
+	}
+
+	logoutCmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored personal access token",
+		Args:  cobra.NoArgs,
+		Run:   runAuthLogout,
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show which account the resolved token belongs to",
+		Args:  cobra.NoArgs,
+		Run:   runAuthStatus,
+	}
+
+	authCmd.AddCommand(loginCmd, logoutCmd, statusCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+func runAuthLogin(cmd *cobra.Command, args []string) {
+	fmt.Fprint(os.Stderr, "Paste your personal access token: ")
+	token, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	token = strings.TrimSpace(token)
+	if token == "" {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		} else {
+			fmt.Fprintln(os.Stderr, "Error: empty token")
+		}
+		os.Exit(1)
+	}
+
+	forge, err := currentForge()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := config.SaveToken(forge, token); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Token stored for %s\n", forge)
+}
+
+func runAuthLogout(cmd *cobra.Command, args []string) {
+	forge, err := currentForge()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := config.DeleteToken(forge); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Token removed for %s\n", forge)
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) {
+	forge, err := currentForge()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if forge != config.ForgeGitHub {
+		if config.ResolveToken(forge, tokenFlag) == "" {
+			fmt.Printf("Not authenticated on %s: requests are anonymous\n", forge)
+		} else {
+			fmt.Printf("Token configured for %s\n", forge)
+		}
+		return
+	}
+
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !client.Authenticated() {
+		fmt.Println("Not authenticated: requests are anonymous")
+		return
+	}
+
+	login, err := client.AuthenticatedUser(cmd.Context())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Authenticated as %s on %s\n", login, client.BaseURL())
+}
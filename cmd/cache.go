@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the on-disk response cache",
+		Long: `Inspect or empty the cache of API responses kept under the user cache directory.
+Cached responses are revalidated with conditional requests, which do not count
+against the rate limit.`,
+	}
+
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached response",
+		Args:  cobra.NoArgs,
+		Run:   runCacheClear,
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show the number and size of cached responses",
+		Args:  cobra.NoArgs,
+		Run:   runCacheStats,
+	}
+
+	// Add flags
+	statsCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format (text/json)")
+
+	cacheCmd.AddCommand(clearCmd, statsCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) {
+	store, err := openCache()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := store.Clear(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Cache cleared")
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) {
+	store, err := openCache()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	stats, err := store.Stats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch formatFlag {
+	case "json":
+		output, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(output))
+	default:
+		fmt.Printf("Directory: %s\n", stats.Dir)
+		fmt.Printf("Entries:   %d\n", stats.Entries)
+		fmt.Printf("Size:      %.1f KiB\n", float64(stats.Size)/1024)
+		if stats.Entries > 0 {
+			fmt.Printf("Oldest:    %s\n", stats.Oldest.Format(time.DateTime))
+			fmt.Printf("Newest:    %s\n", stats.Newest.Format(time.DateTime))
+		}
+	}
+}
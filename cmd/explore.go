@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"ghexplorer/forge"
+	"ghexplorer/localgit"
 	"ghexplorer/model"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -13,6 +15,7 @@ var (
 	userFlag   string
 	outputFlag string
 	formatFlag string
+	localFlag  string
 )
 
 func init() {
@@ -22,14 +25,19 @@ func init() {
 		Long: `Start the TUI application to explore a GitHub profile.
 If a username is provided, it will directly load that profile.
 
+With --local, the git repository containing the given directory is browsed
+instead, with its working tree, branches and tags listed as repositories.
+
 Example:
-  ghexplorer explore octocat`,
+  ghexplorer explore octocat
+  ghexplorer explore --local ./path/to/repo`,
 		Run: runExplore,
 	}
 
 	// Add flags
 	exploreCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output file for saving data")
 	exploreCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format (text/json)")
+	exploreCmd.Flags().StringVar(&localFlag, "local", "", "Browse the local git repository containing this directory")
 
 	rootCmd.AddCommand(exploreCmd)
 }
@@ -40,7 +48,27 @@ func runExplore(cmd *cobra.Command, args []string) {
 		initialGithubID = args[0]
 	}
 
-	p := tea.NewProgram(model.InitialModel(initialGithubID), tea.WithAltScreen())
+	var provider forge.Provider
+	if localFlag != "" {
+		repo, err := localgit.Open(localFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if initialGithubID == "" {
+			initialGithubID = repo.Name()
+		}
+		provider = repo
+	} else {
+		var err error
+		provider, err = newProvider()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	p := tea.NewProgram(model.InitialModel(provider, initialGithubID), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rateLimitCmd := &cobra.Command{
+		Use:   "rate-limit",
+		Short: "Show the remaining API quota",
+		Long: `Display how many API requests are left for each resource and when the quota resets.
+Checking the rate limit does not count against it.
+
+Example:
+  ghexplorer rate-limit`,
+		Args: cobra.NoArgs,
+		Run:  runRateLimit,
+	}
+
+	// Add flags
+	rateLimitCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format (text/json)")
+
+	rootCmd.AddCommand(rateLimitCmd)
+}
+
+func runRateLimit(cmd *cobra.Command, args []string) {
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	limits, err := client.FetchRateLimits(cmd.Context())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch formatFlag {
+	case "json":
+		output, err := json.MarshalIndent(limits, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(output))
+	default:
+		for _, limit := range limits {
+			fmt.Printf("%-28s %s\n", limit.Resource, limit)
+		}
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"os"
 
-	"ghexplorer/github_api"
 	"github.com/spf13/cobra"
 )
 
+var refFlag string
+
 func init() {
 	repoCmd := &cobra.Command{
 		Use:   "repo [username] [repository]",
@@ -17,7 +18,8 @@ func init() {
 This command provides detailed information without starting the TUI.
 
 Example:
-  ghexplorer repo octocat Hello-World`,
+  ghexplorer repo octocat Hello-World
+  ghexplorer repo octocat Hello-World --ref v1.0.0`,
 		Args: cobra.ExactArgs(2),
 		Run:  runRepo,
 	}
@@ -25,6 +27,7 @@ Example:
 	// Add flags
 	repoCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output file for saving data")
 	repoCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format (text/json)")
+	repoCmd.Flags().StringVar(&refFlag, "ref", "", "Branch, tag or commit SHA to list (defaults to the default branch)")
 
 	rootCmd.AddCommand(repoCmd)
 }
@@ -33,8 +36,14 @@ func runRepo(cmd *cobra.Command, args []string) {
 	username := args[0]
 	repository := args[1]
 
+	provider, err := newProvider()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Fetch repository contents
-	contents, err := github_api.FetchRepositoryContents(username, repository, "")
+	contents, err := provider.ListContents(cmd.Context(), username, repository, refFlag, "")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -1,12 +1,33 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"time"
 
+	"ghexplorer/cache"
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"ghexplorer/gitea"
+	"ghexplorer/github_api"
+	"ghexplorer/gitlab"
 	"github.com/spf13/cobra"
 )
 
+var (
+	forgeFlag         string
+	tokenFlag         string
+	baseURLFlag       string
+	rateLimitWaitFlag time.Duration
+	noCacheFlag       bool
+	cacheTTLFlag      time.Duration
+	offlineFlag       bool
+	timeoutFlag       time.Duration
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "ghexplorer",
 	Short: "GitHub Explorer - A TUI tool to explore GitHub profiles",
@@ -15,9 +36,119 @@ explore GitHub profiles, repositories, and files in an interactive way.
 Complete documentation is available at https://github.com/IvanGael/ghexplorer`,
 }
 
+func init() {
+	rootCmd.PersistentFlags().StringVar(&forgeFlag, "forge", "", "Forge to browse: github, gitea or gitlab (defaults to github)")
+	rootCmd.PersistentFlags().StringVar(&baseURLFlag, "base-url", "", "API base URL, e.g. https://github.example.com for GitHub Enterprise Server or the Gitea or GitLab instance URL")
+	rootCmd.PersistentFlags().DurationVar(&rateLimitWaitFlag, "rate-limit-wait", 0, "Wait up to this long and retry when hitting a secondary rate limit (0 fails immediately)")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "Do not read or write the on-disk response cache")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTLFlag, "cache-ttl", config.DefaultCacheTTL, "Serve cached responses younger than this without revalidating them")
+	rootCmd.PersistentFlags().BoolVar(&offlineFlag, "offline", false, "Serve everything from the cache without network access")
+	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", config.DefaultTimeout, "Give up on an API request after this long (0 waits forever)")
+	rootCmd.PersistentFlags().StringVar(&tokenFlag, "token", "", "Personal access token (defaults to GITHUB_TOKEN, GH_TOKEN, GITEA_TOKEN, GITLAB_TOKEN or the stored credential of the forge)")
+}
+
+// newClient creates the GitHub API client configured by the global flags,
+// the environment and the configuration file
+func newClient() (*github_api.Client, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if name := config.ResolveForge(forgeFlag, settings); name != config.ForgeGitHub {
+		return nil, fmt.Errorf("this command is only available for %s, not %s", config.ForgeGitHub, name)
+	}
+	return newGitHubClient(settings)
+}
+
+// newGitHubClient creates the GitHub API client with the given settings
+func newGitHubClient(settings *config.Settings) (*github_api.Client, error) {
+	if offlineFlag && noCacheFlag {
+		return nil, errors.New("--offline cannot be combined with --no-cache")
+	}
+
+	baseURL := config.ResolveBaseURL(config.ForgeGitHub, baseURLFlag, settings)
+	client, err := github_api.NewClient(baseURL, config.ResolveToken(config.ForgeGitHub, tokenFlag))
+	if err != nil {
+		return nil, err
+	}
+	client.SetSecondaryRateLimitWait(rateLimitWaitFlag)
+	client.SetTimeout(resolveTimeout(settings))
+
+	if !noCacheFlag {
+		store, err := openCache()
+		if err != nil {
+			return nil, err
+		}
+		ttl := config.ResolveCacheTTL(cacheTTLFlag, rootCmd.PersistentFlags().Changed("cache-ttl"), settings)
+		client.SetCache(store, ttl)
+		client.SetOffline(offlineFlag)
+	}
+	return client, nil
+}
+
+// newProvider creates the provider of the selected forge for the browsing commands
+func newProvider() (forge.Provider, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	name := config.ResolveForge(forgeFlag, settings)
+	if name != config.ForgeGitHub && offlineFlag {
+		return nil, fmt.Errorf("--offline is only available for %s", config.ForgeGitHub)
+	}
+
+	switch name {
+	case config.ForgeGitHub:
+		return newGitHubClient(settings)
+	case config.ForgeGitea:
+		client, err := gitea.NewClient(config.ResolveBaseURL(name, baseURLFlag, settings), config.ResolveToken(name, tokenFlag))
+		if err != nil {
+			return nil, err
+		}
+		client.SetTimeout(resolveTimeout(settings))
+		return client, nil
+	case config.ForgeGitLab:
+		client, err := gitlab.NewClient(config.ResolveBaseURL(name, baseURLFlag, settings), config.ResolveToken(name, tokenFlag))
+		if err != nil {
+			return nil, err
+		}
+		client.SetTimeout(resolveTimeout(settings))
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unsupported forge %q", name)
+	}
+}
+
+// resolveTimeout returns the request timeout set by the flag or the configuration file
+func resolveTimeout(settings *config.Settings) time.Duration {
+	return config.ResolveTimeout(timeoutFlag, rootCmd.PersistentFlags().Changed("timeout"), settings)
+}
+
+// currentForge returns the forge selected by the flags, the environment or the configuration file
+func currentForge() (string, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return "", err
+	}
+	return config.ResolveForge(forgeFlag, settings), nil
+}
+
+// openCache opens the on-disk response cache
+func openCache() (*cache.Store, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return cache.Open(dir)
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
+// Interrupting the program cancels the requests of the running command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -3,12 +3,21 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"ghexplorer/forge"
+	"ghexplorer/helper"
+	"io"
 	"os"
+	"strings"
+	"time"
 
-	"ghexplorer/github_api"
 	"github.com/spf13/cobra"
 )
 
+var (
+	sortFlag    string
+	filterFlags []string
+)
+
 func init() {
 	searchCmd := &cobra.Command{
 		Use:   "search [username] [query]",
@@ -17,7 +26,8 @@ func init() {
 This command provides search results without starting the TUI.
 
 Example:
-  ghexplorer search octocat "awesome"`,
+  ghexplorer search octocat "awesome"
+  ghexplorer search octocat "awesome" --sort stars --filter language=go --filter hide-forks`,
 		Args: cobra.ExactArgs(2),
 		Run:  runSearch,
 	}
@@ -25,6 +35,8 @@ Example:
 	// Add flags
 	searchCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output file for saving data")
 	searchCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format (text/json)")
+	searchCmd.Flags().StringVar(&sortFlag, "sort", "", "Sort by "+strings.Join(forge.RepositorySorts, ", "))
+	searchCmd.Flags().StringSliceVar(&filterFlags, "filter", nil, "Filter by language=NAME, topic=NAME, hide-forks or hide-archived (repeatable)")
 
 	rootCmd.AddCommand(searchCmd)
 }
@@ -33,12 +45,29 @@ func runSearch(cmd *cobra.Command, args []string) {
 	username := args[0]
 	query := args[1]
 
+	filter, err := forge.ParseRepositoryFilter(filterFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	provider, err := newProvider()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Search repositories
-	repos, err := github_api.SearchRepositories(username, query)
+	repos, err := provider.SearchRepositories(cmd.Context(), username, query)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	repos = filter.Apply(repos)
+	if err := forge.SortRepositories(repos, sortFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Handle output based on format flag
 	switch formatFlag {
@@ -66,12 +95,44 @@ func runSearch(cmd *cobra.Command, args []string) {
 			}
 			defer f.Close()
 			for _, repo := range repos {
-				fmt.Fprintf(f, "Repository: %s\nDescription: %s\n\n", repo.Name, repo.Description)
+				writeRepository(f, repo)
 			}
 		} else {
 			for _, repo := range repos {
-				fmt.Printf("Repository: %s\nDescription: %s\n\n", repo.Name, repo.Description)
+				writeRepository(os.Stdout, repo)
 			}
+			fmt.Printf("View on the web: %s\n", provider.SearchHTMLURL(username, query))
 		}
 	}
 }
+
+// writeRepository prints a repository and the details its forge reported
+func writeRepository(w io.Writer, repo *forge.Repository) {
+	fmt.Fprintf(w, "Repository: %s\nDescription: %s\n", repo.Name, repo.Description)
+	fmt.Fprintf(w, "Stars: %d  Forks: %d  Watchers: %d\n", repo.Stars, repo.Forks, repo.Watchers)
+	if repo.Language != "" {
+		fmt.Fprintf(w, "Language: %s\n", repo.Language)
+	}
+	if license := repo.LicenseName(); license != "" {
+		fmt.Fprintf(w, "License: %s\n", license)
+	}
+	if len(repo.Topics) > 0 {
+		fmt.Fprintf(w, "Topics: %s\n", strings.Join(repo.Topics, ", "))
+	}
+	if flags := repo.Flags(); len(flags) > 0 {
+		fmt.Fprintf(w, "Flags: %s\n", strings.Join(flags, ", "))
+	}
+	if repo.DefaultBranch != "" {
+		fmt.Fprintf(w, "Default branch: %s\n", repo.DefaultBranch)
+	}
+	if !repo.PushedAt.IsZero() {
+		fmt.Fprintf(w, "Pushed: %s\n", repo.PushedAt.Format(time.RFC3339))
+	}
+	if !repo.UpdatedAt.IsZero() {
+		fmt.Fprintf(w, "Updated: %s\n", repo.UpdatedAt.Format(time.RFC3339))
+	}
+	if repo.Size > 0 {
+		fmt.Fprintf(w, "Size: %s\n", helper.FormatSize(repo.Size<<10))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"ghexplorer/forge"
+	"io"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	treeCmd := &cobra.Command{
+		Use:   "tree [username] [repository]",
+		Short: "Print the whole file tree of a repository",
+		Long: `Fetch every file and directory of a repository at once and print them
+as a tree, like tree(1), or as JSON.
+
+Example:
+  ghexplorer tree octocat Hello-World
+  ghexplorer tree octocat Hello-World --ref v1.0.0 -f json`,
+		Args: cobra.ExactArgs(2),
+		Run:  runTree,
+	}
+
+	// Add flags
+	treeCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output file for saving data")
+	treeCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format (text/json)")
+	treeCmd.Flags().StringVar(&refFlag, "ref", "", "Branch, tag or commit SHA to list (defaults to the default branch)")
+
+	rootCmd.AddCommand(treeCmd)
+}
+
+func runTree(cmd *cobra.Command, args []string) {
+	username := args[0]
+	repository := args[1]
+
+	provider, err := newProvider()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	tree, err := provider.GetTree(cmd.Context(), username, repository, refFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if tree.Truncated {
+		fmt.Fprintln(os.Stderr, "Warning: the tree is too large to be listed entirely; some entries are missing")
+	}
+
+	// Handle output based on format flag
+	switch formatFlag {
+	case "json":
+		output, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if outputFlag != "" {
+			err = os.WriteFile(outputFlag, output, 0644)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing to file: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Println(string(output))
+		}
+	default:
+		if outputFlag != "" {
+			f, err := os.Create(outputFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating file: %v\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			writeTree(f, path.Base(repository), tree)
+		} else {
+			writeTree(os.Stdout, path.Base(repository), tree)
+		}
+	}
+}
+
+// writeTree prints tree below root with the box-drawing branches of tree(1),
+// followed by the directory and file counts
+func writeTree(w io.Writer, root string, tree *forge.Tree) {
+	byDir := tree.ByDir()
+	dirs, files := 0, 0
+
+	var walk func(dir, indent string)
+	walk = func(dir, indent string) {
+		entries := byDir[dir]
+		for i, entry := range entries {
+			branch, next := "├── ", "│   "
+			if i == len(entries)-1 {
+				branch, next = "└── ", "    "
+			}
+			name := entry.Name
+			switch entry.Type {
+			case "dir":
+				dirs++
+			case "symlink":
+				files++
+				if entry.Target != "" {
+					name += " -> " + entry.Target
+				}
+			default:
+				files++
+			}
+			fmt.Fprintf(w, "%s%s%s\n", indent, branch, name)
+			if entry.Type == "dir" {
+				walk(entry.Path, indent+next)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, root)
+	walk("", "")
+	fmt.Fprintf(w, "\n%d %s, %d %s\n", dirs, plural(dirs, "directory", "directories"), files, plural(files, "file", "files"))
+}
+
+// plural picks the singular or plural form for n
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenEnvVars are the environment variables checked for a token of each forge, in order
+var TokenEnvVars = map[string][]string{
+	ForgeGitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	ForgeGitea:  {"GITEA_TOKEN"},
+	ForgeGitLab: {"GITLAB_TOKEN"},
+}
+
+// Dir returns the ghexplorer configuration directory
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ghexplorer"), nil
+}
+
+// TokenFile returns the path of the credential stored for forge
+func TokenFile(forge string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if forge == ForgeGitHub {
+		return filepath.Join(dir, "token"), nil
+	}
+	return filepath.Join(dir, forge+"-token"), nil
+}
+
+// ResolveToken returns the token to authenticate with on forge, preferring the
+// flag value, then the environment and finally the stored credential
+func ResolveToken(forge, flagToken string) string {
+	if flagToken != "" {
+		return flagToken
+	}
+	for _, name := range TokenEnvVars[forge] {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	token, _ := LoadToken(forge)
+	return token
+}
+
+// LoadToken reads the credential stored for forge, returning an empty string if none is stored
+func LoadToken(forge string) (string, error) {
+	path, err := TokenFile(forge)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SaveToken stores the credential for forge readable by the current user only
+func SaveToken(forge, token string) error {
+	path, err := TokenFile(forge)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.TrimSpace(token)+"\n"), 0600)
+}
+
+// DeleteToken removes the credential stored for forge
+func DeleteToken(forge string) error {
+	path, err := TokenFile(forge)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -15,6 +15,31 @@ const (
 	PaginationStyle = "• %d/%d •"
 )
 
+const (
+	// SyntaxTheme is the chroma style highlighting file contents
+	SyntaxTheme = "monokai"
+	// MaxHighlightSize is the size above which files are shown without highlighting
+	MaxHighlightSize = 256 << 10
+	// HighlightCacheSize is the number of highlighted files kept
+	HighlightCacheSize = 32
+)
+
+const (
+	// ProfileHeight is the height of the Overview tab without the profile README
+	ProfileHeight = 24
+	// MinReadmeHeight is the least number of profile README lines shown
+	MinReadmeHeight = 5
+	// ReadmePreviewLines is the number of repository README lines shown below its listing
+	ReadmePreviewLines = 20
+)
+
+const (
+	// TabWidth is the number of columns tabs are expanded to in the file viewer
+	TabWidth = 4
+	// ScrollStep is the number of columns ←/→ scroll unwrapped lines by
+	ScrollStep = 8
+)
+
 var (
 	RepositoryStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#00ffff"))
 	FolderStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("33"))
@@ -24,6 +49,22 @@ var (
 	TabStyle        = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, true, false, true).Padding(0, 1)
 	ActiveTabStyle  = TabStyle.Border(lipgloss.DoubleBorder(), true, true, false, true)
 	SpinnerStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	StaleStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Italic(true)
+	BannerStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#FF3333")).Padding(0, 1)
+	MatchStyle      = lipgloss.NewStyle().Bold(true).Underline(true)
+	GutterStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	// SearchMatchStyle marks the matches found in a file, CurrentMatchStyle the one shown
+	SearchMatchStyle  = lipgloss.NewStyle().Background(lipgloss.Color("240"))
+	CurrentMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("214")).Foreground(lipgloss.Color("0"))
+)
+
+// Markdown styles
+var (
+	MarkdownHeadingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("87")).Bold(true)
+	MarkdownCodeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	MarkdownLinkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("33")).Underline(true)
+	MarkdownQuoteStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+	MarkdownRuleStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 )
 
 var UseHighPerformanceRenderer = false
@@ -61,14 +102,17 @@ var (
 			Foreground(lipgloss.Color("241")).
 			Width(10)
 
+	// DetailStyle Secondary details styles
+	DetailStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241"))
+
 	// ValueStyle Valus styles
 	ValueStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("255"))
 
-	// README styles
-	// readmeStyle = lipgloss.NewStyle().
-	// 		Border(lipgloss.RoundedBorder()).
-	// 		BorderForeground(lipgloss.Color("63")).
-	// 		Padding(1).
-	// 		Width(60)
+	// ReadmeStyle README styles
+	ReadmeStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("63")).
+			Padding(0, 1)
 )
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Supported forges
+const (
+	ForgeGitHub = "github"
+	ForgeGitea  = "gitea"
+	ForgeGitLab = "gitlab"
+)
+
+// ForgeEnvVar is the environment variable selecting the forge
+const ForgeEnvVar = "GHEXPLORER_FORGE"
+
+// BaseURLEnvVars are the environment variables checked for the API base URL of each forge, in order
+var BaseURLEnvVars = map[string][]string{
+	ForgeGitHub: {"GHEXPLORER_BASE_URL", "GH_HOST"},
+	ForgeGitea:  {"GHEXPLORER_BASE_URL", "GITEA_URL"},
+	ForgeGitLab: {"GHEXPLORER_BASE_URL", "GITLAB_HOST"},
+}
+
+// DefaultCacheTTL is how long cached responses are served without revalidation
+const DefaultCacheTTL = 5 * time.Minute
+
+// DefaultTimeout bounds each API request, including reading its response
+const DefaultTimeout = 30 * time.Second
+
+// Duration is a time.Duration written as a string such as "10m" in the configuration file
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Settings holds the preferences read from the configuration file
+type Settings struct {
+	Forge    string    `json:"forge"`
+	BaseURL  string    `json:"base_url"`
+	CacheTTL *Duration `json:"cache_ttl"`
+	Timeout  *Duration `json:"timeout"`
+}
+
+// ResolveCacheTTL returns the cache TTL set by the flag if changed, else the configuration
+// file, else DefaultCacheTTL
+func ResolveCacheTTL(flagTTL time.Duration, flagChanged bool, settings *Settings) time.Duration {
+	if flagChanged {
+		return flagTTL
+	}
+	if settings != nil && settings.CacheTTL != nil {
+		return time.Duration(*settings.CacheTTL)
+	}
+	return DefaultCacheTTL
+}
+
+// ResolveTimeout returns the request timeout set by the flag if changed, else the
+// configuration file, else DefaultTimeout
+func ResolveTimeout(flagTimeout time.Duration, flagChanged bool, settings *Settings) time.Duration {
+	if flagChanged {
+		return flagTimeout
+	}
+	if settings != nil && settings.Timeout != nil {
+		return time.Duration(*settings.Timeout)
+	}
+	return DefaultTimeout
+}
+
+// SettingsFile returns the path of the configuration file
+func SettingsFile() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// LoadSettings reads the configuration file, returning empty settings if it does not exist
+func LoadSettings() (*Settings, error) {
+	settings := &Settings{}
+	path, err := SettingsFile()
+	if err != nil {
+		return settings, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return settings, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// ResolveForge returns the forge to browse, preferring the flag value, then the
+// environment and finally the configuration file. It defaults to GitHub.
+func ResolveForge(flagForge string, settings *Settings) string {
+	if flagForge != "" {
+		return flagForge
+	}
+	if forge := os.Getenv(ForgeEnvVar); forge != "" {
+		return forge
+	}
+	if settings != nil && settings.Forge != "" {
+		return settings.Forge
+	}
+	return ForgeGitHub
+}
+
+// ResolveBaseURL returns the API base URL of forge, preferring the flag value, then
+// the environment and finally the configuration file. An empty result means the
+// forge's public instance, if it has one.
+func ResolveBaseURL(forge, flagBaseURL string, settings *Settings) string {
+	if flagBaseURL != "" {
+		return flagBaseURL
+	}
+	for _, name := range BaseURLEnvVars[forge] {
+		if baseURL := os.Getenv(name); baseURL != "" {
+			return baseURL
+		}
+	}
+	if settings != nil {
+		return settings.BaseURL
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package forge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Kinds of API errors, matched with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrTooLarge     = errors.New("too large")
+	ErrNetwork      = errors.New("network error")
+)
+
+// APIError is a failed API request
+type APIError struct {
+	// Kind is one of the Err* kinds, or nil when the failure is not classified
+	Kind error
+	// What names the data being fetched, such as "profile"
+	What string
+	// Status is the HTTP status line of the response, if any
+	Status           string
+	Message          string
+	DocumentationURL string
+	// SSOURL is where to authorize the token for an organization enforcing SAML single sign-on
+	SSOURL string
+	// Err is the transport error of requests that got no response
+	Err error
+}
+
+// Error describes the failed request
+func (e *APIError) Error() string {
+	msg := "failed to fetch " + e.What
+	if e.What == "" {
+		msg = "request failed"
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	if e.Status != "" {
+		msg += ": " + e.Status
+	}
+	if e.Message != "" && !strings.HasSuffix(strings.ToLower(e.Status), strings.ToLower(e.Message)) {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is reports whether target is the kind of the error
+func (e *APIError) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+// Unwrap returns the transport error
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// StatusKind classifies an HTTP status code
+func StatusKind(code int) error {
+	switch code {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusRequestEntityTooLarge:
+		return ErrTooLarge
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	default:
+		return nil
+	}
+}
+
+// ResponseError builds the error of an unsuccessful response, reading the message
+// and documentation link of the usual {"message": ..., "documentation_url": ...} body
+func ResponseError(resp *http.Response, what string) *APIError {
+	apiErr := &APIError{Kind: StatusKind(resp.StatusCode), What: what, Status: resp.Status}
+
+	var body struct {
+		Message          json.RawMessage `json:"message"`
+		Error            string          `json:"error"`
+		DocumentationURL string          `json:"documentation_url"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) == nil {
+		// GitLab reports validation failures as an object
+		if json.Unmarshal(body.Message, &apiErr.Message) != nil {
+			apiErr.Message = strings.TrimSpace(string(body.Message))
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = body.Error
+		}
+		apiErr.DocumentationURL = body.DocumentationURL
+	}
+	return apiErr
+}
+
+// NetworkError wraps the error of a request that got no response
+func NetworkError(err error, what string) *APIError {
+	return &APIError{Kind: ErrNetwork, What: what, Err: err}
+}
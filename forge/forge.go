@@ -0,0 +1,300 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profile is a user or organization profile
+type Profile struct {
+	Name        string `json:"name"`
+	Login       string `json:"login"`
+	Description string `json:"bio"`
+	Followers   int    `json:"followers"`
+	Following   int    `json:"following"`
+}
+
+// Repository is a profile repository
+type Repository struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Path identifies the repository below its owner when it differs from Name,
+	// as for GitLab projects in subgroups
+	Path          string    `json:"path,omitempty"`
+	Stars         int       `json:"stargazers_count"`
+	Forks         int       `json:"forks_count"`
+	Watchers      int       `json:"watchers_count"`
+	Language      string    `json:"language"`
+	Topics        []string  `json:"topics"`
+	License       *License  `json:"license"`
+	Archived      bool      `json:"archived"`
+	Fork          bool      `json:"fork"`
+	Private       bool      `json:"private"`
+	DefaultBranch string    `json:"default_branch"`
+	PushedAt      time.Time `json:"pushed_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// Size is the size of the repository in kilobytes
+	Size int64 `json:"size"`
+}
+
+// License is the license detected in a repository
+type License struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	SPDXID string `json:"spdx_id"`
+}
+
+// Flags lists the archived, fork and private states set on the repository
+func (r *Repository) Flags() []string {
+	var flags []string
+	if r.Archived {
+		flags = append(flags, "archived")
+	}
+	if r.Fork {
+		flags = append(flags, "fork")
+	}
+	if r.Private {
+		flags = append(flags, "private")
+	}
+	return flags
+}
+
+// LicenseName returns the SPDX identifier of the license, or its name when it has none
+func (r *Repository) LicenseName() string {
+	switch {
+	case r.License == nil:
+		return ""
+	case r.License.SPDXID != "" && r.License.SPDXID != "NOASSERTION":
+		return r.License.SPDXID
+	default:
+		return r.License.Name
+	}
+}
+
+// Slug returns the identifier to pass as repo to the Provider methods
+func (r *Repository) Slug() string {
+	if r.Path != "" {
+		return r.Path
+	}
+	return r.Name
+}
+
+// FileInfo is a repository directory entry. Type is "file", "dir", "symlink" or "submodule".
+type FileInfo struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Path        string `json:"path,omitempty"`
+	Size        int64  `json:"size"`
+	SHA         string `json:"sha,omitempty"`
+	HTMLURL     string `json:"html_url,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+	// SizeUnknown is set when the forge does not report the size of the file
+	SizeUnknown bool `json:"size_unknown,omitempty"`
+	// Target is where a symlink points, relative to its directory or, with a
+	// leading slash, to the repository root
+	Target string `json:"target,omitempty"`
+	// SubmoduleGitURL is the clone URL of a submodule
+	SubmoduleGitURL string `json:"submodule_git_url,omitempty"`
+}
+
+// MaxFileSize caps the content read for a file; larger files are truncated
+const MaxFileSize = 10 << 20
+
+// File is the content of a repository file and its metadata
+type File struct {
+	Path string `json:"path"`
+	// Size is the size of the whole file, which may exceed len(Content)
+	Size int64  `json:"size"`
+	SHA  string `json:"sha,omitempty"`
+	// Encoding is how the API delivered the content, such as "base64" or "raw"
+	Encoding  string `json:"encoding,omitempty"`
+	Binary    bool   `json:"binary"`
+	Truncated bool   `json:"truncated"`
+	Content   []byte `json:"-"`
+}
+
+// SetContent stores data, truncated to MaxFileSize, and detects binary content
+func (f *File) SetContent(data []byte) {
+	if len(data) > MaxFileSize {
+		data = data[:MaxFileSize]
+		f.Truncated = true
+	}
+	if int64(len(data)) > f.Size {
+		f.Size = int64(len(data))
+	}
+	f.Content = data
+	f.Binary = IsBinary(data)
+}
+
+// ReadContent reads the content from r, stopping after MaxFileSize bytes
+func (f *File) ReadContent(r io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileSize+1))
+	if err != nil {
+		return err
+	}
+	f.SetContent(data)
+	return nil
+}
+
+// IsBinary reports whether data looks like binary rather than text content:
+// its first 8000 bytes hold a NUL byte, as git checks, or more than a tenth of
+// them are control characters. Text in another encoding than UTF-8, such as
+// Latin-1, is not binary.
+func IsBinary(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+	control := 0
+	for _, b := range head {
+		if isControl(b) {
+			control++
+		}
+	}
+	return control*10 > len(head)
+}
+
+// isControl reports whether b is a control character other than the
+// whitespace and escape characters of text
+func isControl(b byte) bool {
+	switch b {
+	case '\t', '\n', '\v', '\f', '\r', '\x1b':
+		return false
+	}
+	return b < 0x20 || b == 0x7f
+}
+
+// Tree is the recursive listing of a repository. Its entries carry their path;
+// submodule URLs, and symlink targets outside local repositories, are not
+// resolved.
+type Tree struct {
+	Entries []*FileInfo `json:"entries"`
+	// Truncated is set when the forge returned only part of the tree
+	Truncated bool `json:"truncated"`
+}
+
+// ByDir groups the entries by the path of their directory, the root being the
+// empty path, sorted by name
+func (t *Tree) ByDir() map[string][]*FileInfo {
+	dirs := make(map[string][]*FileInfo)
+	for _, entry := range t.Entries {
+		dir := path.Dir(strings.Trim(entry.Path, "/"))
+		if dir == "." {
+			dir = ""
+		}
+		dirs[dir] = append(dirs[dir], entry)
+	}
+	for _, entries := range dirs {
+		sort.Slice(entries, func(i, j int) bool { return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name) })
+	}
+	return dirs
+}
+
+// EntryType maps the mode and object type of a git tree entry onto file types
+func EntryType(mode, kind string) string {
+	switch {
+	case kind == "tree":
+		return "dir"
+	case kind == "commit":
+		return "submodule"
+	case mode == "120000":
+		return "symlink"
+	default:
+		return "file"
+	}
+}
+
+// Ref is a branch or tag of a repository
+type Ref struct {
+	Name string `json:"name"`
+	// SHA is the commit the ref points to
+	SHA string `json:"sha"`
+}
+
+// Provider is a source of profiles, repositories and files such as a code forge.
+// Paths are relative to the repository root and may start with a slash. Files are
+// read at ref, a branch, tag or commit SHA, the empty ref being the default branch.
+// Fetches are abandoned when their context is done.
+type Provider interface {
+	GetProfile(ctx context.Context, username string) (*Profile, error)
+	ListRepositories(ctx context.Context, username string) ([]*Repository, error)
+	ListBranches(ctx context.Context, owner, repo string) ([]*Ref, error)
+	ListTags(ctx context.Context, owner, repo string) ([]*Ref, error)
+	ListContents(ctx context.Context, owner, repo, ref, path string) ([]*FileInfo, error)
+	GetTree(ctx context.Context, owner, repo, ref string) (*Tree, error)
+	GetFile(ctx context.Context, owner, repo, ref, path string) (*File, error)
+	GetReadme(ctx context.Context, owner, repo, ref string) (*File, error)
+	SearchRepositories(ctx context.Context, username, query string) ([]*Repository, error)
+	FileHTMLURL(owner, repo, ref, path string) string
+	SearchHTMLURL(username, query string) string
+}
+
+// RateLimit is the request quota reported by an API
+type RateLimit struct {
+	Resource  string    `json:"resource,omitempty"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+}
+
+// String renders the quota for status lines
+func (r RateLimit) String() string {
+	return fmt.Sprintf("%d/%d requests left, resets at %s", r.Remaining, r.Limit, r.Reset.Local().Format("15:04"))
+}
+
+// RateLimiter is implemented by providers reporting their API quota
+type RateLimiter interface {
+	// RateLimit returns the quota reported by the most recent response
+	RateLimit() (RateLimit, bool)
+}
+
+// ErrNotCached is returned in offline mode for data missing from the cache
+var ErrNotCached = errors.New("not available offline")
+
+// CacheTrace records the age of the cached responses served to a traced provider
+type CacheTrace struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+// Record notes a response stored at storedAt
+func (t *CacheTrace) Record(storedAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.oldest.IsZero() || storedAt.Before(t.oldest) {
+		t.oldest = storedAt
+	}
+}
+
+// Oldest returns when the oldest cached response served was stored,
+// or false if every response came from the network
+func (t *CacheTrace) Oldest() (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.oldest, !t.oldest.IsZero()
+}
+
+// EntryDescriber is implemented by providers whose listings omit symlink
+// targets or submodule URLs, found for an entry on demand
+type EntryDescriber interface {
+	// DescribeEntry returns entry, listed in owner's repo at ref, with its
+	// symlink target or submodule URL
+	DescribeEntry(ctx context.Context, owner, repo, ref string, entry *FileInfo) (*FileInfo, error)
+}
+
+// CacheTracer is implemented by providers that can serve data from a local cache
+type CacheTracer interface {
+	// Offline reports whether data is served from the cache only
+	Offline() bool
+	// Traced returns a provider reporting the cached responses it serves to trace
+	Traced(trace *CacheTrace) Provider
+}
@@ -0,0 +1,59 @@
+package forge
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBinary(t *testing.T) {
+	assert.False(t, IsBinary([]byte("package main\n")))
+	assert.False(t, IsBinary([]byte("héllo wörld ✓")))
+	assert.False(t, IsBinary(nil))
+	assert.True(t, IsBinary([]byte("PK\x03\x04\x00\x00")))
+	assert.True(t, IsBinary([]byte("\x01\x02\x03\x04ab\x05")))
+
+	// Text that is not UTF-8 is still text
+	assert.False(t, IsBinary([]byte("caf\xe9 cr\xe8me br\xfbl\xe9e\r\n")))
+
+	// A rune cut at the 8000 byte boundary does not make text binary
+	text := []byte(strings.Repeat("a", 7999) + "é")
+	assert.False(t, IsBinary(text))
+}
+
+func TestSetContent(t *testing.T) {
+	var file File
+	assert.NoError(t, file.ReadContent(bytes.NewReader(make([]byte, MaxFileSize+10))))
+	assert.True(t, file.Truncated)
+	assert.Len(t, file.Content, MaxFileSize)
+	assert.Equal(t, int64(MaxFileSize), file.Size)
+
+	file = File{Size: 3}
+	file.SetContent([]byte("abc"))
+	assert.False(t, file.Truncated)
+	assert.Equal(t, int64(3), file.Size)
+}
+
+func TestTreeByDir(t *testing.T) {
+	tree := Tree{Entries: []*FileInfo{
+		{Name: "src", Type: "dir", Path: "src"},
+		{Name: "main.go", Type: "file", Path: "src/main.go"},
+		{Name: "README", Type: "file", Path: "README"},
+		{Name: "go.mod", Type: "file", Path: "src/go.mod"},
+	}}
+	dirs := tree.ByDir()
+	assert.Len(t, dirs, 2)
+	assert.Equal(t, "README", dirs[""][0].Name)
+	if assert.Len(t, dirs["src"], 2) {
+		assert.Equal(t, "go.mod", dirs["src"][0].Name)
+	}
+}
+
+func TestEntryType(t *testing.T) {
+	assert.Equal(t, "dir", EntryType("040000", "tree"))
+	assert.Equal(t, "submodule", EntryType("160000", "commit"))
+	assert.Equal(t, "symlink", EntryType("120000", "blob"))
+	assert.Equal(t, "file", EntryType("100755", "blob"))
+}
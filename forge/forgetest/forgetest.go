@@ -0,0 +1,298 @@
+// Package forgetest provides an in-memory forge.Provider for tests
+package forgetest
+
+import (
+	"context"
+	"fmt"
+	"ghexplorer/forge"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Fake is an in-memory forge.Provider. Directory listings are derived from the
+// paths of the files added to it. Files at another ref than the default branch
+// are added to the repository "repo@ref".
+type Fake struct {
+	Profiles     map[string]*forge.Profile
+	Repositories map[string][]*forge.Repository
+	// Branches and Tags map "owner/repo" to refs
+	Branches map[string][]*forge.Ref
+	Tags     map[string][]*forge.Ref
+	// Files maps "owner/repo/path" to file content
+	Files map[string]string
+	// Links maps "owner/repo/path" to symlink and submodule entries
+	Links map[string]*forge.FileInfo
+	// DescribeLinks, when set, makes listings omit symlink targets and
+	// submodule URLs, returned by DescribeEntry instead
+	DescribeLinks bool
+	// Err, when set, is returned by every call
+	Err error
+	// Hold, when set, makes every call wait until it is closed or the context is done
+	Hold chan struct{}
+}
+
+var (
+	_ forge.Provider       = (*Fake)(nil)
+	_ forge.EntryDescriber = (*Fake)(nil)
+)
+
+// NewFake returns an empty fake provider
+func NewFake() *Fake {
+	return &Fake{
+		Profiles:     make(map[string]*forge.Profile),
+		Repositories: make(map[string][]*forge.Repository),
+		Branches:     make(map[string][]*forge.Ref),
+		Tags:         make(map[string][]*forge.Ref),
+		Files:        make(map[string]string),
+		Links:        make(map[string]*forge.FileInfo),
+	}
+}
+
+// AddProfile registers a profile
+func (f *Fake) AddProfile(profile *forge.Profile) {
+	f.Profiles[profile.Login] = profile
+}
+
+// AddRepository registers a repository of owner
+func (f *Fake) AddRepository(owner string, repo *forge.Repository) {
+	f.Repositories[owner] = append(f.Repositories[owner], repo)
+}
+
+// AddBranch registers a branch of owner's repo
+func (f *Fake) AddBranch(owner, repo, name, sha string) {
+	f.Branches[owner+"/"+repo] = append(f.Branches[owner+"/"+repo], &forge.Ref{Name: name, SHA: sha})
+}
+
+// AddTag registers a tag of owner's repo
+func (f *Fake) AddTag(owner, repo, name, sha string) {
+	f.Tags[owner+"/"+repo] = append(f.Tags[owner+"/"+repo], &forge.Ref{Name: name, SHA: sha})
+}
+
+// AddFile registers a file of owner's repo
+func (f *Fake) AddFile(owner, repo, path, content string) {
+	f.Files[fileKey(owner, repo, path)] = content
+}
+
+// wait blocks on Hold and returns the error the call should fail with, if any
+func (f *Fake) wait(ctx context.Context) error {
+	if f.Hold != nil {
+		select {
+		case <-f.Hold:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.Err
+}
+
+// AddSymlink registers a symlink of owner's repo pointing to target
+func (f *Fake) AddSymlink(owner, repo, path, target string) {
+	f.Links[fileKey(owner, repo, path)] = &forge.FileInfo{Type: "symlink", Target: target}
+}
+
+// AddSubmodule registers a submodule of owner's repo cloned from gitURL
+func (f *Fake) AddSubmodule(owner, repo, path, gitURL string) {
+	f.Links[fileKey(owner, repo, path)] = &forge.FileInfo{Type: "submodule", SubmoduleGitURL: gitURL}
+}
+
+// atRef returns the repository the files of repo at ref are added to
+func atRef(repo, ref string) string {
+	if ref == "" {
+		return repo
+	}
+	return repo + "@" + ref
+}
+
+// fileKey builds the Files key of a path
+func fileKey(owner, repo, path string) string {
+	return owner + "/" + repo + "/" + strings.Trim(path, "/")
+}
+
+// GetProfile returns the registered profile
+func (f *Fake) GetProfile(ctx context.Context, username string) (*forge.Profile, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	profile, ok := f.Profiles[username]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found", username)
+	}
+	return profile, nil
+}
+
+// ListRepositories returns the repositories registered for username
+func (f *Fake) ListRepositories(ctx context.Context, username string) ([]*forge.Repository, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.Repositories[username], nil
+}
+
+// ListBranches returns the branches registered for owner's repo
+func (f *Fake) ListBranches(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.Branches[owner+"/"+repo], nil
+}
+
+// ListTags returns the tags registered for owner's repo
+func (f *Fake) ListTags(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.Tags[owner+"/"+repo], nil
+}
+
+// ListContents lists the files and directories directly below path
+func (f *Fake) ListContents(ctx context.Context, owner, repo, ref, path string) ([]*forge.FileInfo, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	repo = atRef(repo, ref)
+	prefix := fileKey(owner, repo, path) + "/"
+	if strings.Trim(path, "/") == "" {
+		prefix = owner + "/" + repo + "/"
+	}
+
+	seen := make(map[string]bool)
+	var contents []*forge.FileInfo
+	for key := range f.Files {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		name, _, isDir := strings.Cut(rest, "/")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		entry := &forge.FileInfo{Name: name, Type: "file", Path: strings.TrimPrefix(prefix+name, owner+"/"+repo+"/")}
+		if isDir {
+			entry.Type = "dir"
+		} else {
+			entry.Size = int64(len(f.Files[key]))
+		}
+		contents = append(contents, entry)
+	}
+	for key, link := range f.Links {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok || strings.Contains(rest, "/") {
+			continue
+		}
+		entry := *link
+		entry.Name = rest
+		entry.Path = strings.TrimPrefix(key, owner+"/"+repo+"/")
+		if f.DescribeLinks {
+			entry.Target = ""
+			entry.SubmoduleGitURL = ""
+		}
+		contents = append(contents, &entry)
+	}
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("path %s not found in %s/%s", path, owner, repo)
+	}
+	sort.Slice(contents, func(i, j int) bool { return contents[i].Name < contents[j].Name })
+	return contents, nil
+}
+
+// DescribeEntry returns entry with the target or URL of the registered link
+func (f *Fake) DescribeEntry(ctx context.Context, owner, repo, ref string, entry *forge.FileInfo) (*forge.FileInfo, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	link, ok := f.Links[fileKey(owner, atRef(repo, ref), entry.Path)]
+	if !ok {
+		return nil, fmt.Errorf("link %s not found in %s/%s", entry.Path, owner, repo)
+	}
+	described := *entry
+	described.Target = link.Target
+	described.SubmoduleGitURL = link.SubmoduleGitURL
+	return &described, nil
+}
+
+// GetTree lists every file, directory and link registered for owner's repo
+func (f *Fake) GetTree(ctx context.Context, owner, repo, ref string) (*forge.Tree, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	prefix := owner + "/" + atRef(repo, ref) + "/"
+
+	tree := &forge.Tree{}
+	seen := make(map[string]bool)
+	add := func(p string, entry forge.FileInfo) {
+		entry.Name, entry.Path = path.Base(p), p
+		tree.Entries = append(tree.Entries, &entry)
+		seen[p] = true
+	}
+	for key, content := range f.Files {
+		p, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		add(p, forge.FileInfo{Type: "file", Size: int64(len(content))})
+		for dir := path.Dir(p); dir != "." && !seen[dir]; dir = path.Dir(dir) {
+			add(dir, forge.FileInfo{Type: "dir"})
+		}
+	}
+	for key, link := range f.Links {
+		if p, ok := strings.CutPrefix(key, prefix); ok {
+			add(p, *link)
+		}
+	}
+	if len(tree.Entries) == 0 {
+		return nil, fmt.Errorf("repository %s/%s not found", owner, repo)
+	}
+	sort.Slice(tree.Entries, func(i, j int) bool { return tree.Entries[i].Path < tree.Entries[j].Path })
+	return tree, nil
+}
+
+// GetReadme returns the registered README at the root of the repository
+func (f *Fake) GetReadme(ctx context.Context, owner, repo, ref string) (*forge.File, error) {
+	return forge.ReadmeOf(ctx, f, owner, repo, ref)
+}
+
+// GetFile returns the registered file content
+func (f *Fake) GetFile(ctx context.Context, owner, repo, ref, path string) (*forge.File, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	repo = atRef(repo, ref)
+	content, ok := f.Files[fileKey(owner, repo, path)]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found in %s/%s", path, owner, repo)
+	}
+	file := &forge.File{Path: strings.Trim(path, "/")}
+	file.SetContent([]byte(content))
+	return file, nil
+}
+
+// SearchRepositories matches query against the names and descriptions of username's repositories
+func (f *Fake) SearchRepositories(ctx context.Context, username, query string) ([]*forge.Repository, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(query)
+	var repos []*forge.Repository
+	for _, repo := range f.Repositories[username] {
+		if strings.Contains(strings.ToLower(repo.Name), query) || strings.Contains(strings.ToLower(repo.Description), query) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+// FileHTMLURL returns a fake web link to a file
+func (f *Fake) FileHTMLURL(owner, repo, ref, path string) string {
+	return "https://forge.test/" + fileKey(owner, atRef(repo, ref), path)
+}
+
+// SearchHTMLURL returns a fake web link to a search
+func (f *Fake) SearchHTMLURL(username, query string) string {
+	return "https://forge.test/search?q=" + url.QueryEscape(query+" user:"+username)
+}
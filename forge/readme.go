@@ -0,0 +1,47 @@
+package forge
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// readmeExtensions ranks the README formats, Markdown first
+var readmeExtensions = []string{".md", ".markdown", "", ".txt", ".rst", ".adoc", ".org"}
+
+// FindReadme picks the README among the entries of a directory, preferring
+// Markdown, or returns nil when there is none. READMEs in other formats, such
+// as PDF, are ignored.
+func FindReadme(contents []*FileInfo) *FileInfo {
+	var readme *FileInfo
+	best := len(readmeExtensions)
+	for _, entry := range contents {
+		if entry.Type != "file" && entry.Type != "symlink" {
+			continue
+		}
+		ext := path.Ext(entry.Name)
+		if !strings.EqualFold(strings.TrimSuffix(entry.Name, ext), "readme") {
+			continue
+		}
+		for rank, readmeExt := range readmeExtensions {
+			if strings.EqualFold(ext, readmeExt) && rank < best {
+				readme, best = entry, rank
+			}
+		}
+	}
+	return readme
+}
+
+// ReadmeOf fetch the README at the root of owner's repo through its listing,
+// for forges with no README endpoint
+func ReadmeOf(ctx context.Context, provider Provider, owner, repo, ref string) (*File, error) {
+	contents, err := provider.ListContents(ctx, owner, repo, ref, "")
+	if err != nil {
+		return nil, err
+	}
+	readme := FindReadme(contents)
+	if readme == nil {
+		return nil, &APIError{Kind: ErrNotFound, What: "README"}
+	}
+	return provider.GetFile(ctx, owner, repo, ref, "/"+readme.Name)
+}
@@ -0,0 +1,21 @@
+package forge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindReadme(t *testing.T) {
+	contents := []*FileInfo{
+		{Name: "readme.txt", Type: "file"},
+		{Name: "README.md", Type: "file"},
+		{Name: "README", Type: "dir"},
+		{Name: "main.go", Type: "file"},
+	}
+	assert.Equal(t, "README.md", FindReadme(contents).Name)
+	assert.Equal(t, "readme.txt", FindReadme(contents[:1]).Name)
+	assert.Nil(t, FindReadme(contents[2:]))
+	assert.Equal(t, "Readme.rst", FindReadme([]*FileInfo{{Name: "README.pdf", Type: "file"}, {Name: "Readme.rst", Type: "file"}}).Name)
+	assert.Nil(t, FindReadme([]*FileInfo{{Name: "README.pdf", Type: "file"}}))
+}
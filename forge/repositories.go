@@ -0,0 +1,122 @@
+package forge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RepositorySorts lists the keys repositories can be sorted by
+var RepositorySorts = []string{"stars", "name", "pushed", "size"}
+
+// SortRepositories orders repos by key: most starred, alphabetical, most recently
+// pushed or largest first. Ties keep their order.
+func SortRepositories(repos []*Repository, key string) error {
+	var less func(a, b *Repository) bool
+	switch key {
+	case "":
+		return nil
+	case "stars":
+		less = func(a, b *Repository) bool { return a.Stars > b.Stars }
+	case "name":
+		less = func(a, b *Repository) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "pushed":
+		less = func(a, b *Repository) bool { return a.lastPush().After(b.lastPush()) }
+	case "size":
+		less = func(a, b *Repository) bool { return a.Size > b.Size }
+	default:
+		return fmt.Errorf("unknown sort %q, expected one of %s", key, strings.Join(RepositorySorts, ", "))
+	}
+	sort.SliceStable(repos, func(i, j int) bool { return less(repos[i], repos[j]) })
+	return nil
+}
+
+// lastPush returns when the repository was last pushed to, falling back on its
+// last update for forges that do not report pushes
+func (r *Repository) lastPush() time.Time {
+	if r.PushedAt.IsZero() {
+		return r.UpdatedAt
+	}
+	return r.PushedAt
+}
+
+// RepositoryFilter selects repositories by language and topic, optionally
+// hiding forks and archived repositories
+type RepositoryFilter struct {
+	Language     string
+	Topic        string
+	HideForks    bool
+	HideArchived bool
+}
+
+// ParseRepositoryFilter reads the language=NAME, topic=NAME, hide-forks and
+// hide-archived filters
+func ParseRepositoryFilter(specs []string) (RepositoryFilter, error) {
+	var f RepositoryFilter
+	for _, spec := range specs {
+		key, value, _ := strings.Cut(strings.TrimSpace(spec), "=")
+		switch key {
+		case "language":
+			f.Language = value
+		case "topic":
+			f.Topic = value
+		case "hide-forks":
+			f.HideForks = true
+		case "hide-archived":
+			f.HideArchived = true
+		default:
+			return f, fmt.Errorf("unknown filter %q, expected language=NAME, topic=NAME, hide-forks or hide-archived", spec)
+		}
+	}
+	return f, nil
+}
+
+// Match reports whether repo passes the filter. Languages and topics are
+// compared ignoring case.
+func (f RepositoryFilter) Match(repo *Repository) bool {
+	if f.HideForks && repo.Fork || f.HideArchived && repo.Archived {
+		return false
+	}
+	if f.Language != "" && !strings.EqualFold(f.Language, repo.Language) {
+		return false
+	}
+	if f.Topic == "" {
+		return true
+	}
+	for _, topic := range repo.Topics {
+		if strings.EqualFold(f.Topic, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the repositories passing the filter
+func (f RepositoryFilter) Apply(repos []*Repository) []*Repository {
+	matches := make([]*Repository, 0, len(repos))
+	for _, repo := range repos {
+		if f.Match(repo) {
+			matches = append(matches, repo)
+		}
+	}
+	return matches
+}
+
+// String lists the active filters in the syntax ParseRepositoryFilter reads
+func (f RepositoryFilter) String() string {
+	var specs []string
+	if f.Language != "" {
+		specs = append(specs, "language="+f.Language)
+	}
+	if f.Topic != "" {
+		specs = append(specs, "topic="+f.Topic)
+	}
+	if f.HideForks {
+		specs = append(specs, "hide-forks")
+	}
+	if f.HideArchived {
+		specs = append(specs, "hide-archived")
+	}
+	return strings.Join(specs, ", ")
+}
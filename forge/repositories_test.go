@@ -0,0 +1,56 @@
+package forge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRepositories() []*Repository {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	return []*Repository{
+		{Name: "beta", Stars: 5, Size: 300, Language: "Go", PushedAt: day(3), Topics: []string{"cli"}},
+		{Name: "Alpha", Stars: 50, Size: 10, Language: "C", UpdatedAt: day(9), Fork: true},
+		{Name: "gamma", Stars: 5, Size: 20, Language: "go", PushedAt: day(1), Archived: true, Topics: []string{"CLI", "tui"}},
+	}
+}
+
+func names(repos []*Repository) []string {
+	var n []string
+	for _, repo := range repos {
+		n = append(n, repo.Name)
+	}
+	return n
+}
+
+func TestSortRepositories(t *testing.T) {
+	for key, expected := range map[string][]string{
+		"":       {"beta", "Alpha", "gamma"},
+		"stars":  {"Alpha", "beta", "gamma"},
+		"name":   {"Alpha", "beta", "gamma"},
+		"pushed": {"Alpha", "beta", "gamma"},
+		"size":   {"beta", "gamma", "Alpha"},
+	} {
+		repos := testRepositories()
+		assert.NoError(t, SortRepositories(repos, key))
+		assert.Equal(t, expected, names(repos), key)
+	}
+	assert.Error(t, SortRepositories(testRepositories(), "forks"))
+}
+
+func TestRepositoryFilter(t *testing.T) {
+	f, err := ParseRepositoryFilter([]string{"language=GO", "hide-archived"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"beta"}, names(f.Apply(testRepositories())))
+	assert.Equal(t, "language=GO, hide-archived", f.String())
+
+	f, err = ParseRepositoryFilter([]string{"topic=cli", "hide-forks"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"beta", "gamma"}, names(f.Apply(testRepositories())))
+
+	assert.Len(t, RepositoryFilter{}.Apply(testRepositories()), 3)
+
+	_, err = ParseRepositoryFilter([]string{"stars=5"})
+	assert.Error(t, err)
+}
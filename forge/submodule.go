@@ -0,0 +1,74 @@
+package forge
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ParseGitmodules maps the paths of the submodules declared in a .gitmodules
+// file to their URLs
+func ParseGitmodules(content string) map[string]string {
+	urls := make(map[string]string)
+	var modulePath, moduleURL string
+	flush := func() {
+		if modulePath != "" && moduleURL != "" {
+			urls[modulePath] = moduleURL
+		}
+		modulePath, moduleURL = "", ""
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "path":
+			modulePath = strings.Trim(strings.TrimSpace(value), "/")
+		case "url":
+			moduleURL = strings.TrimSpace(value)
+		}
+	}
+	flush()
+	return urls
+}
+
+// SubmoduleRepository returns the owner and name of the repository a submodule
+// URL points to. Relative URLs are resolved against the repository of the
+// superproject, owner's repo.
+func SubmoduleRepository(gitURL, owner, repo string) (string, string, error) {
+	var p string
+	switch {
+	case strings.HasPrefix(gitURL, "./"), strings.HasPrefix(gitURL, "../"):
+		p = path.Join(owner, repo, gitURL)
+	case strings.Contains(gitURL, "://"):
+		u, err := url.Parse(gitURL)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid submodule URL %q: %w", gitURL, err)
+		}
+		p = u.Path
+	default:
+		// scp-like syntax: git@host:owner/repo.git
+		_, rest, ok := strings.Cut(gitURL, ":")
+		if !ok {
+			return "", "", fmt.Errorf("invalid submodule URL %q", gitURL)
+		}
+		p = rest
+	}
+
+	p = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	submoduleOwner, submoduleRepo, ok := strings.Cut(p, "/")
+	if !ok || submoduleOwner == "" || submoduleRepo == "" {
+		return "", "", fmt.Errorf("submodule URL %q does not name a repository", gitURL)
+	}
+	return submoduleOwner, submoduleRepo, nil
+}
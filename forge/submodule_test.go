@@ -0,0 +1,38 @@
+package forge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitmodules(t *testing.T) {
+	urls := ParseGitmodules(`[submodule "lib"]
+	path = vendor/lib
+	url = https://github.com/octocat/lib.git
+[submodule "docs"]
+	url = git@github.com:octocat/docs.git
+	path = docs
+`)
+	assert.Equal(t, map[string]string{
+		"vendor/lib": "https://github.com/octocat/lib.git",
+		"docs":       "git@github.com:octocat/docs.git",
+	}, urls)
+}
+
+func TestSubmoduleRepository(t *testing.T) {
+	for gitURL, want := range map[string][2]string{
+		"https://github.com/octocat/Spoon-Knife.git":  {"octocat", "Spoon-Knife"},
+		"git@github.com:octocat/Spoon-Knife.git":      {"octocat", "Spoon-Knife"},
+		"../Spoon-Knife.git":                          {"octocat", "Spoon-Knife"},
+		"../../github/linguist":                       {"github", "linguist"},
+		"https://gitlab.com/gitlab-org/ci/runner.git": {"gitlab-org", "ci/runner"},
+	} {
+		owner, repo, err := SubmoduleRepository(gitURL, "octocat", "Hello-World")
+		assert.NoError(t, err, gitURL)
+		assert.Equal(t, want, [2]string{owner, repo}, gitURL)
+	}
+
+	_, _, err := SubmoduleRepository("https://example.com/", "octocat", "Hello-World")
+	assert.Error(t, err)
+}
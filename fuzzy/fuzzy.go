@@ -0,0 +1,127 @@
+// Package fuzzy matches patterns against names the way fuzzy finders do: the
+// characters of the pattern must appear in order, and matches on word starts
+// and consecutive characters rank higher.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scores of the matched characters
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 8
+	bonusBoundary    = 10
+	bonusCase        = 1
+	penaltyGap       = 1
+)
+
+// Match is a match of a pattern in a string
+type Match struct {
+	Score int
+	// Positions are the indexes of the matched runes
+	Positions []int
+}
+
+// Ranked is a match of a pattern in one of the strings ranked by Rank
+type Ranked struct {
+	Match
+	Index int
+}
+
+// Find matches pattern against s, ignoring case and the spaces of the pattern
+func Find(pattern, s string) (Match, bool) {
+	runes := []rune(strings.ReplaceAll(pattern, " ", ""))
+	if len(runes) == 0 {
+		return Match{}, true
+	}
+	text := []rune(s)
+	p, lower := toLower(runes), toLower(text)
+
+	var best Match
+	found := false
+	for start := range lower {
+		if lower[start] != p[0] {
+			continue
+		}
+		positions := make([]int, 0, len(p))
+		for i, j := start, 0; i < len(lower) && j < len(p); i++ {
+			if lower[i] == p[j] {
+				positions = append(positions, i)
+				j++
+			}
+		}
+		if len(positions) < len(p) {
+			// Later starts cannot match either
+			break
+		}
+		if score := scoreOf(text, runes, positions); !found || score > best.Score {
+			best = Match{Score: score, Positions: positions}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// toLower lowers each rune, keeping the indexes of runes
+func toLower(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// scoreOf rates the runes of text matched by the pattern runes at positions
+func scoreOf(text, pattern []rune, positions []int) int {
+	score := 0
+	for k, i := range positions {
+		score += scoreMatch
+		if text[i] == pattern[k] {
+			score += bonusCase
+		}
+		if isBoundary(text, i) {
+			score += bonusBoundary
+		}
+		if k > 0 {
+			if gap := i - positions[k-1] - 1; gap == 0 {
+				score += bonusConsecutive
+			} else {
+				score -= gap * penaltyGap
+			}
+		}
+	}
+	return score
+}
+
+// isBoundary reports whether the rune at i starts a word: the first rune, one
+// following a separator or an upper case letter following a lower case one
+func isBoundary(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := text[i-1]
+	switch {
+	case strings.ContainsRune("/-_. ", prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(text[i]):
+		return true
+	default:
+		return false
+	}
+}
+
+// Rank matches pattern against each of items and returns the matches, best
+// first. Equal matches keep the order of items.
+func Rank(pattern string, items []string) []Ranked {
+	var ranked []Ranked
+	for i, item := range items {
+		if match, ok := Find(pattern, item); ok {
+			ranked = append(ranked, Ranked{Match: match, Index: i})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	return ranked
+}
@@ -0,0 +1,41 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	match, ok := Find("hw", "Hello-World")
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 6}, match.Positions)
+
+	match, ok = Find("ÉT", "café-été")
+	assert.True(t, ok)
+	assert.Equal(t, []int{5, 6}, match.Positions)
+
+	_, ok = Find("wh", "Hello-World")
+	assert.False(t, ok)
+
+	match, ok = Find("", "anything")
+	assert.True(t, ok)
+	assert.Empty(t, match.Positions)
+
+	// The best placement is chosen, not the first one
+	match, _ = Find("mod", "my-model")
+	assert.Equal(t, []int{3, 4, 5}, match.Positions)
+}
+
+func TestRank(t *testing.T) {
+	ranked := Rank("rd", []string{"README.md", "src/render.go", "ribbon-dance", "docs"})
+	var order []int
+	for _, r := range ranked {
+		order = append(order, r.Index)
+	}
+	assert.Equal(t, []int{2, 1, 0}, order)
+
+	// Camel case humps are word starts
+	ranked = Rank("fi", []string{"profile", "FileInfo"})
+	assert.Equal(t, 1, ranked[0].Index)
+}
@@ -0,0 +1,429 @@
+package gitea
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ghexplorer/forge"
+	"ghexplorer/helper"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pageSize is the number of items requested per page, the default maximum of Gitea
+const pageSize = 50
+
+// Client is a Gitea or Forgejo API client
+type Client struct {
+	httpClient *http.Client
+	token      string
+	baseURL    *url.URL
+	webURL     *url.URL
+
+	mu       sync.Mutex
+	branches map[string]string
+}
+
+var _ forge.Provider = (*Client)(nil)
+
+// user is the Gitea user payload
+type user struct {
+	ID          int64  `json:"id"`
+	Login       string `json:"login"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Followers   int    `json:"followers_count"`
+	Following   int    `json:"following_count"`
+}
+
+// repository is the Gitea repository payload
+type repository struct {
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	DefaultBranch string    `json:"default_branch"`
+	Stars         int       `json:"stars_count"`
+	Forks         int       `json:"forks_count"`
+	Watchers      int       `json:"watchers_count"`
+	Language      string    `json:"language"`
+	Topics        []string  `json:"topics"`
+	Archived      bool      `json:"archived"`
+	Fork          bool      `json:"fork"`
+	Private       bool      `json:"private"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Size          int64     `json:"size"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// NewClient creates a client for the Gitea instance at baseURL sending the given
+// access token. The API is served below /api/v1 of the instance.
+func NewClient(baseURL, token string) (*Client, error) {
+	if baseURL == "" {
+		return nil, errors.New("a base URL is required for Gitea")
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	path = strings.TrimSuffix(path, "/api/v1")
+	return &Client{
+		httpClient: http.DefaultClient,
+		token:      token,
+		baseURL:    &url.URL{Scheme: u.Scheme, Host: u.Host, Path: path + "/api/v1"},
+		webURL:     &url.URL{Scheme: u.Scheme, Host: u.Host, Path: path},
+		branches:   make(map[string]string),
+	}, nil
+}
+
+// SetTimeout bounds each request, including reading its response. Zero disables the timeout.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.httpClient = &http.Client{Timeout: timeout}
+}
+
+// do performs an authenticated GET request, failing on unsuccessful responses
+func (c *Client) do(ctx context.Context, customUrl, what string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, customUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, forge.NetworkError(err, what)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := forge.ResponseError(resp, what)
+		resp.Body.Close()
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+// get performs an authenticated GET request and decodes the JSON response into v
+func (c *Client) get(ctx context.Context, customUrl, what string, v any) error {
+	resp, err := c.do(ctx, customUrl, what)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// rememberBranches records the default branches used to build file links
+func (c *Client) rememberBranches(repos []*repository) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, repo := range repos {
+		c.branches[repo.Owner.Login+"/"+repo.Name] = repo.DefaultBranch
+	}
+}
+
+// convertRepositories maps Gitea repositories to forge repositories
+func (c *Client) convertRepositories(repos []*repository) []*forge.Repository {
+	c.rememberBranches(repos)
+	converted := make([]*forge.Repository, 0, len(repos))
+	for _, repo := range repos {
+		converted = append(converted, &forge.Repository{
+			Name:          repo.Name,
+			Description:   repo.Description,
+			Stars:         repo.Stars,
+			Forks:         repo.Forks,
+			Watchers:      repo.Watchers,
+			Language:      repo.Language,
+			Topics:        repo.Topics,
+			Archived:      repo.Archived,
+			Fork:          repo.Fork,
+			Private:       repo.Private,
+			DefaultBranch: repo.DefaultBranch,
+			UpdatedAt:     repo.UpdatedAt,
+			Size:          repo.Size,
+		})
+	}
+	return converted
+}
+
+// fetchUser fetch a Gitea user or organization
+func (c *Client) fetchUser(ctx context.Context, username string) (*user, error) {
+	var u user
+	err := c.get(ctx, helper.JoinURL(c.baseURL, nil, "users", username), "profile", &u)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetProfile fetch Gitea profile
+func (c *Client) GetProfile(ctx context.Context, username string) (*forge.Profile, error) {
+	u, err := c.fetchUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return &forge.Profile{
+		Name:        u.FullName,
+		Login:       u.Login,
+		Description: u.Description,
+		Followers:   u.Followers,
+		Following:   u.Following,
+	}, nil
+}
+
+// ListRepositories fetch Gitea profile repositories with pagination
+func (c *Client) ListRepositories(ctx context.Context, username string) ([]*forge.Repository, error) {
+	var allRepos []*repository
+	for page := 1; ; page++ {
+		query := url.Values{
+			"page":  {strconv.Itoa(page)},
+			"limit": {strconv.Itoa(pageSize)},
+		}
+		var repos []*repository
+		err := c.get(ctx, helper.JoinURL(c.baseURL, query, "users", username, "repos"), "repositories", &repos)
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repos...)
+		if len(repos) < pageSize {
+			break
+		}
+	}
+	return c.convertRepositories(allRepos), nil
+}
+
+// refQuery returns the query selecting ref, none for the default branch
+func refQuery(ref string) url.Values {
+	if ref == "" {
+		return nil
+	}
+	return url.Values{"ref": {ref}}
+}
+
+// ListBranches fetch Gitea repository branches with pagination
+func (c *Client) ListBranches(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	var refs []*forge.Ref
+	for page := 1; ; page++ {
+		query := url.Values{
+			"page":  {strconv.Itoa(page)},
+			"limit": {strconv.Itoa(pageSize)},
+		}
+		var branches []struct {
+			Name   string `json:"name"`
+			Commit struct {
+				ID string `json:"id"`
+			} `json:"commit"`
+		}
+		err := c.get(ctx, helper.JoinURL(c.baseURL, query, "repos", owner, repo, "branches"), "branches", &branches)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			refs = append(refs, &forge.Ref{Name: branch.Name, SHA: branch.Commit.ID})
+		}
+		if len(branches) < pageSize {
+			return refs, nil
+		}
+	}
+}
+
+// ListTags fetch Gitea repository tags with pagination
+func (c *Client) ListTags(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	var refs []*forge.Ref
+	for page := 1; ; page++ {
+		query := url.Values{
+			"page":  {strconv.Itoa(page)},
+			"limit": {strconv.Itoa(pageSize)},
+		}
+		var tags []struct {
+			Name   string `json:"name"`
+			Commit struct {
+				SHA string `json:"sha"`
+			} `json:"commit"`
+		}
+		err := c.get(ctx, helper.JoinURL(c.baseURL, query, "repos", owner, repo, "tags"), "tags", &tags)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			refs = append(refs, &forge.Ref{Name: tag.Name, SHA: tag.Commit.SHA})
+		}
+		if len(tags) < pageSize {
+			return refs, nil
+		}
+	}
+}
+
+// ListContents fetch Gitea profile repository contents
+func (c *Client) ListContents(ctx context.Context, owner, repo, ref, path string) ([]*forge.FileInfo, error) {
+	var contents []*forge.FileInfo
+	err := c.get(ctx, helper.JoinURL(c.baseURL, refQuery(ref), "repos", owner, repo, "contents", path), "repository contents", &contents)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// GetTree fetch the whole Gitea repository tree, which is paginated
+func (c *Client) GetTree(ctx context.Context, owner, repo, ref string) (*forge.Tree, error) {
+	if ref == "" {
+		c.mu.Lock()
+		ref = c.branches[owner+"/"+repo]
+		c.mu.Unlock()
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	tree := &forge.Tree{}
+	for page := 1; ; page++ {
+		query := url.Values{
+			"recursive": {"true"},
+			"page":      {strconv.Itoa(page)},
+			"per_page":  {strconv.Itoa(pageSize)},
+		}
+		var level struct {
+			Tree []struct {
+				Path string `json:"path"`
+				Mode string `json:"mode"`
+				Type string `json:"type"`
+				SHA  string `json:"sha"`
+				Size int64  `json:"size"`
+			} `json:"tree"`
+			Truncated bool `json:"truncated"`
+		}
+		err := c.get(ctx, helper.JoinURL(c.baseURL, query, "repos", owner, repo, "git", "trees", ref), "repository tree", &level)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range level.Tree {
+			tree.Entries = append(tree.Entries, &forge.FileInfo{
+				Name: path.Base(entry.Path),
+				Type: forge.EntryType(entry.Mode, entry.Type),
+				Path: entry.Path,
+				SHA:  entry.SHA,
+				Size: entry.Size,
+			})
+		}
+		// Gitea marks every page but the last as truncated
+		if !level.Truncated || len(level.Tree) == 0 {
+			return tree, nil
+		}
+	}
+}
+
+// GetReadme fetch the README at the root of a Gitea repository
+func (c *Client) GetReadme(ctx context.Context, owner, repo, ref string) (*forge.File, error) {
+	return forge.ReadmeOf(ctx, c, owner, repo, ref)
+}
+
+// GetFile fetch Gitea profile repository file contents. Files over the inline
+// size limit of the instance, whose content is omitted, are streamed raw.
+func (c *Client) GetFile(ctx context.Context, owner, repo, ref, path string) (*forge.File, error) {
+	var fileContent struct {
+		Path     string  `json:"path"`
+		SHA      string  `json:"sha"`
+		Size     int64   `json:"size"`
+		Content  *string `json:"content"`
+		Encoding *string `json:"encoding"`
+	}
+	err := c.get(ctx, helper.JoinURL(c.baseURL, refQuery(ref), "repos", owner, repo, "contents", path), "file content", &fileContent)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &forge.File{Path: fileContent.Path, SHA: fileContent.SHA, Size: fileContent.Size}
+	switch {
+	case fileContent.Content == nil:
+		resp, err := c.do(ctx, helper.JoinURL(c.baseURL, refQuery(ref), "repos", owner, repo, "raw", path), "file content")
+		if err != nil {
+			return nil, err
+		}
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+
+			}
+		}(resp.Body)
+		file.Encoding = "raw"
+		if err := file.ReadContent(resp.Body); err != nil {
+			return nil, err
+		}
+	case fileContent.Encoding != nil && *fileContent.Encoding == "base64":
+		file.Encoding = *fileContent.Encoding
+		decodedContent, err := base64.StdEncoding.DecodeString(*fileContent.Content)
+		if err != nil {
+			return nil, err
+		}
+		file.SetContent(decodedContent)
+	default:
+		file.SetContent([]byte(*fileContent.Content))
+	}
+	return file, nil
+}
+
+// SearchRepositories perform searching through Gitea profile repositories
+func (c *Client) SearchRepositories(ctx context.Context, username, query string) ([]*forge.Repository, error) {
+	u, err := c.fetchUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResult struct {
+		Data []*repository `json:"data"`
+	}
+	values := url.Values{
+		"q":         {query},
+		"uid":       {strconv.FormatInt(u.ID, 10)},
+		"exclusive": {"true"},
+		"limit":     {strconv.Itoa(pageSize)},
+	}
+	err = c.get(ctx, helper.JoinURL(c.baseURL, values, "repos", "search"), "search results", &searchResult)
+	if err != nil {
+		return nil, err
+	}
+	return c.convertRepositories(searchResult.Data), nil
+}
+
+// FileHTMLURL returns the web page of a file at ref, the default branch when empty
+func (c *Client) FileHTMLURL(owner, repo, ref, path string) string {
+	if ref != "" {
+		// Gitea redirects these legacy links to the branch, tag or commit named ref
+		return helper.JoinURL(c.webURL, nil, owner, repo, "src", ref, path)
+	}
+	c.mu.Lock()
+	branch := c.branches[owner+"/"+repo]
+	c.mu.Unlock()
+	if branch == "" {
+		return helper.JoinURL(c.webURL, nil, owner, repo, "src", path)
+	}
+	return helper.JoinURL(c.webURL, nil, owner, repo, "src", "branch", branch, path)
+}
+
+// SearchHTMLURL returns the web page listing the repositories of username matching query
+func (c *Client) SearchHTMLURL(username, query string) string {
+	return helper.JoinURL(c.webURL, url.Values{"tab": {"repositories"}, "q": {query}}, username)
+}
@@ -0,0 +1,156 @@
+package gitea
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"ghexplorer/forge"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUsername is the Gitea username used for testing.
+const TestUsername = "gitea"
+
+// fixtures maps API paths below /api/v1 to the JSON bodies served by the test server.
+var fixtures = map[string]string{
+	"/users/gitea":                                   `{"id":7,"login":"gitea","full_name":"Gitea","description":"Git with a cup of tea","followers_count":3,"following_count":1}`,
+	"/users/gitea/repos":                             `[{"name":"tea","description":"A command line tool","default_branch":"main","stars_count":12,"forks_count":3,"language":"Go","archived":true,"updated_at":"2024-05-01T10:00:00Z","owner":{"login":"gitea"}}]`,
+	"/repos/gitea/tea/contents":                      `[{"name":"README.md","type":"file"},{"name":"cmd","type":"dir"}]`,
+	"/repos/gitea/tea/contents/README.md":            fmt.Sprintf(`{"content":%q,"encoding":"base64"}`, base64.StdEncoding.EncodeToString([]byte("# tea\n"))),
+	"/repos/search":                                  `{"ok":true,"data":[{"name":"tea","description":"A command line tool","default_branch":"main","owner":{"login":"gitea"}}]}`,
+	"/repos/gitea/tea/branches":                      `[{"name":"main","commit":{"id":"a1b2c3d4e5"}}]`,
+	"/repos/gitea/tea/tags":                          `[{"name":"v0.9.0","id":"f0f0f0","commit":{"sha":"0a1b2c3d4e"}}]`,
+	"/repos/gitea/tea/contents/README.md?ref=v0.9.0": fmt.Sprintf(`{"content":%q,"encoding":"base64"}`, base64.StdEncoding.EncodeToString([]byte("# tea 0.9\n"))),
+	"/repos/gitea/tea/git/trees/main":                `{"sha":"a1b2c3d4e5","tree":[{"path":"README.md","mode":"100644","type":"blob","sha":"5d1e","size":6},{"path":"cmd","mode":"040000","type":"tree","sha":"c0d"}],"truncated":true}`,
+	"/repos/gitea/tea/git/trees/main?page=2":         `{"sha":"a1b2c3d4e5","tree":[{"path":"cmd/main.go","mode":"100644","type":"blob","sha":"6a1","size":42}],"truncated":false}`,
+}
+
+// newTestClient starts a Gitea stand-in serving fixtures and returns a client pointed at it.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		key := r.URL.Path[len("/api/v1"):]
+		if ref := r.URL.Query().Get("ref"); ref != "" {
+			key += "?ref=" + ref
+		}
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			key += "?page=" + page
+		}
+		body, ok := fixtures[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Path == "/api/v1/repos/search" {
+			assert.Equal(t, "7", r.URL.Query().Get("uid"))
+			assert.Equal(t, "tea", r.URL.Query().Get("q"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "secret")
+	assert.NoError(t, err)
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient("", "")
+	assert.Error(t, err)
+
+	client, err := NewClient("gitea.example.com/api/v1/", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitea.example.com/api/v1", client.baseURL.String())
+	assert.Equal(t, "https://gitea.example.com/gitea/tea/src/README.md", client.FileHTMLURL("gitea", "tea", "", "/README.md"))
+	assert.Equal(t, "https://gitea.example.com/gitea/tea/src/v0.9.0/README.md", client.FileHTMLURL("gitea", "tea", "v0.9.0", "/README.md"))
+	assert.Equal(t, "https://gitea.example.com/gitea?q=tea&tab=repositories", client.SearchHTMLURL("gitea", "tea"))
+}
+
+func TestGetProfile(t *testing.T) {
+	profile, err := newTestClient(t).GetProfile(context.Background(), TestUsername)
+	assert.NoError(t, err)
+	assert.Equal(t, "Gitea", profile.Name)
+	assert.Equal(t, "Git with a cup of tea", profile.Description)
+	assert.Equal(t, 3, profile.Followers)
+}
+
+func TestListRepositories(t *testing.T) {
+	client := newTestClient(t)
+	repos, err := client.ListRepositories(context.Background(), TestUsername)
+	assert.NoError(t, err)
+	assert.Len(t, repos, 1)
+	assert.Equal(t, "tea", repos[0].Name)
+	assert.Equal(t, 12, repos[0].Stars)
+	assert.Equal(t, "Go", repos[0].Language)
+	assert.Equal(t, []string{"archived"}, repos[0].Flags())
+	assert.Equal(t, 2024, repos[0].UpdatedAt.Year())
+	assert.Contains(t, client.FileHTMLURL(TestUsername, "tea", "", "README.md"), "/gitea/tea/src/branch/main/README.md")
+}
+
+func TestListContents(t *testing.T) {
+	contents, err := newTestClient(t).ListContents(context.Background(), TestUsername, "tea", "", "")
+	assert.NoError(t, err)
+	assert.Len(t, contents, 2)
+	assert.Equal(t, "dir", contents[1].Type)
+}
+
+func TestGetFile(t *testing.T) {
+	content, err := newTestClient(t).GetFile(context.Background(), TestUsername, "tea", "", "/README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# tea\n", string(content.Content))
+
+	_, err = newTestClient(t).GetFile(context.Background(), TestUsername, "tea", "", "missing.md")
+	assert.ErrorIs(t, err, forge.ErrNotFound)
+}
+
+func TestGetReadme(t *testing.T) {
+	readme, err := newTestClient(t).GetReadme(context.Background(), TestUsername, "tea", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "# tea\n", string(readme.Content))
+
+	_, err = newTestClient(t).GetReadme(context.Background(), TestUsername, "missing", "")
+	assert.ErrorIs(t, err, forge.ErrNotFound)
+}
+
+func TestRefs(t *testing.T) {
+	client := newTestClient(t)
+	branches, err := client.ListBranches(context.Background(), TestUsername, "tea")
+	assert.NoError(t, err)
+	assert.Equal(t, []*forge.Ref{{Name: "main", SHA: "a1b2c3d4e5"}}, branches)
+
+	tags, err := client.ListTags(context.Background(), TestUsername, "tea")
+	assert.NoError(t, err)
+	assert.Equal(t, []*forge.Ref{{Name: "v0.9.0", SHA: "0a1b2c3d4e"}}, tags)
+
+	content, err := client.GetFile(context.Background(), TestUsername, "tea", "v0.9.0", "/README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# tea 0.9\n", string(content.Content))
+}
+
+func TestGetTree(t *testing.T) {
+	client := newTestClient(t)
+	_, err := client.ListRepositories(context.Background(), TestUsername)
+	assert.NoError(t, err)
+
+	// The tree of the default branch is listed over every page
+	tree, err := client.GetTree(context.Background(), TestUsername, "tea", "")
+	assert.NoError(t, err)
+	assert.False(t, tree.Truncated)
+	if assert.Len(t, tree.Entries, 3) {
+		assert.Equal(t, &forge.FileInfo{Name: "main.go", Type: "file", Path: "cmd/main.go", SHA: "6a1", Size: 42}, tree.Entries[2])
+		assert.Equal(t, "dir", tree.Entries[1].Type)
+	}
+}
+
+func TestSearchRepositories(t *testing.T) {
+	repos, err := newTestClient(t).SearchRepositories(context.Background(), TestUsername, "tea")
+	assert.NoError(t, err)
+	assert.Len(t, repos, 1)
+	assert.Equal(t, "tea", repos[0].Name)
+}
@@ -0,0 +1,280 @@
+package github_api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"ghexplorer/cache"
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"ghexplorer/helper"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client is an authenticated GitHub API client
+type Client struct {
+	httpClient *http.Client
+	token      string
+	baseURL    *url.URL
+	webURL     *url.URL
+
+	maxRetryWait time.Duration
+
+	cache    *cache.Store
+	cacheTTL time.Duration
+	offline  bool
+	trace    *forge.CacheTrace
+
+	// state is shared with the copies made by Traced
+	state *clientState
+}
+
+var (
+	_ forge.Provider    = (*Client)(nil)
+	_ forge.RateLimiter = (*Client)(nil)
+	_ forge.CacheTracer = (*Client)(nil)
+)
+
+// clientState is the mutable state shared by a client and its traced copies
+type clientState struct {
+	mu    sync.Mutex
+	login string
+
+	rateMu  sync.Mutex
+	rate    forge.RateLimit
+	hasRate bool
+}
+
+// NewClient creates a GitHub API client for the API at baseURL sending the given
+// personal access token. An empty baseURL targets github.com and an empty token
+// makes anonymous requests.
+func NewClient(baseURL, token string) (*Client, error) {
+	apiURL, webURL, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		httpClient: http.DefaultClient,
+		token:      token,
+		baseURL:    apiURL,
+		webURL:     webURL,
+		state:      &clientState{},
+	}, nil
+}
+
+// parseBaseURL resolves the API and web roots for a github.com or GitHub Enterprise
+// Server base URL. Enterprise APIs are served below /api/v3 of the instance host.
+func parseBaseURL(baseURL string) (apiURL, webURL *url.URL, err error) {
+	if baseURL == "" {
+		baseURL = config.GithubAPIBaseURL
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Host == "" {
+		return nil, nil, fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "github.com" || host == "api.github.com" {
+		apiURL, _ = url.Parse(config.GithubAPIBaseURL)
+		webURL = &url.URL{Scheme: "https", Host: "github.com"}
+		return apiURL, webURL, nil
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	path = strings.TrimSuffix(path, "/api/v3")
+	webURL = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: path}
+	apiURL = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: path + "/api/v3"}
+	return apiURL, webURL, nil
+}
+
+// Authenticated reports whether the client sends a token
+func (c *Client) Authenticated() bool {
+	return c.token != ""
+}
+
+// BaseURL returns the root of the API the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL.String()
+}
+
+// endpoint builds an API URL from escaped path segments and an optional query.
+// Segments may themselves contain slashes, as repository paths do.
+func (c *Client) endpoint(query url.Values, segments ...string) string {
+	return helper.JoinURL(c.baseURL, query, segments...)
+}
+
+// webLink builds a URL on the web interface of the GitHub instance
+func (c *Client) webLink(query url.Values, segments ...string) string {
+	return helper.JoinURL(c.webURL, query, segments...)
+}
+
+// FileHTMLURL returns the web page of a file at ref, the default branch when empty
+func (c *Client) FileHTMLURL(username, repo, ref, path string) string {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return c.webLink(nil, username, repo, "blob", ref, path)
+}
+
+// SearchHTMLURL returns the web page listing the results of a repository search
+func (c *Client) SearchHTMLURL(username, query string) string {
+	return c.webLink(url.Values{
+		"q":    {fmt.Sprintf("%s user:%s", query, username)},
+		"type": {"repositories"},
+	}, "search")
+}
+
+// SetTimeout bounds each request, including reading its response. Zero disables the timeout.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.httpClient = &http.Client{Timeout: timeout}
+}
+
+// SetCache makes the client keep responses in store. Responses younger than ttl
+// are served without contacting the API; older ones are revalidated with
+// conditional requests, whose 304 answers do not count against the rate limit.
+func (c *Client) SetCache(store *cache.Store, ttl time.Duration) {
+	c.cache = store
+	c.cacheTTL = ttl
+}
+
+// get performs an authenticated GET request, going through the cache when one is set
+func (c *Client) get(ctx context.Context, customUrl string) (*http.Response, error) {
+	return c.getMedia(ctx, customUrl, "")
+}
+
+// getMedia is get for the media type accept, the default JSON one when empty.
+// Responses of other media types are cached apart, up to forge.MaxFileSize+1
+// bytes as they are file contents.
+func (c *Client) getMedia(ctx context.Context, customUrl, accept string) (*http.Response, error) {
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	if c.cache == nil {
+		return c.send(ctx, customUrl, header)
+	}
+
+	key := cache.Key(customUrl, c.token)
+	limit := int64(-1)
+	if accept != "" {
+		key = cache.Key(accept+" "+customUrl, c.token)
+		limit = forge.MaxFileSize + 1
+	}
+	entry, cached := c.cache.Get(key)
+	if cached && (c.offline || time.Since(entry.StoredAt) < c.cacheTTL) {
+		return c.cachedResponse(entry), nil
+	}
+
+	if cached {
+		if entry.ETag != "" {
+			header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.send(ctx, customUrl, header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		_ = c.cache.Put(key, entry)
+		return c.cachedResponse(entry), nil
+	case resp.StatusCode == http.StatusOK:
+		var body []byte
+		if limit < 0 {
+			body, err = io.ReadAll(resp.Body)
+		} else {
+			body, err = io.ReadAll(io.LimitReader(resp.Body, limit))
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		_ = c.cache.Put(key, &cache.Entry{
+			URL:          customUrl,
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			StoredAt:     time.Now(),
+		})
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	default:
+		return resp, nil
+	}
+}
+
+// cachedResponse replays a cache entry as a successful response
+func (c *Client) cachedResponse(entry *cache.Entry) *http.Response {
+	if c.trace != nil {
+		c.trace.Record(entry.StoredAt)
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(entry.Body)),
+	}
+}
+
+// send performs an authenticated GET request with extra headers, bypassing the cache.
+// Nothing is sent in offline mode.
+// Rate limited responses are returned as a *RateLimitError, after waiting out
+// secondary limits when allowed to.
+func (c *Client) send(ctx context.Context, customUrl string, header http.Header) (*http.Response, error) {
+	if c.offline {
+		return nil, fmt.Errorf("%w: %s", forge.ErrNotCached, customUrl)
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, customUrl, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, forge.NetworkError(err, "")
+		}
+		c.recordRateLimit(resp.Header)
+
+		rateErr := checkRateLimit(resp)
+		if rateErr == nil {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if !rateErr.Secondary || c.maxRetryWait == 0 || rateErr.RetryAfter > c.maxRetryWait || attempt >= maxSecondaryRetries {
+			return nil, rateErr
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(rateErr.RetryAfter):
+		}
+	}
+}
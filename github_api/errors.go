@@ -0,0 +1,41 @@
+package github_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"ghexplorer/forge"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// apiError classifies an unsuccessful response, recognizing the refusals of
+// organizations enforcing SAML single sign-on and of files too large for the API
+func apiError(resp *http.Response, what string) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	apiErr := forge.ResponseError(resp, what)
+
+	if sso := resp.Header.Get("X-GitHub-SSO"); sso != "" {
+		apiErr.Kind = forge.ErrForbidden
+		for _, part := range strings.Split(sso, ";") {
+			if u, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+				apiErr.SSOURL = u
+			}
+		}
+	}
+
+	var body struct {
+		Errors []struct {
+			Code string `json:"code"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		for _, e := range body.Errors {
+			if e.Code == "too_large" {
+				apiErr.Kind = forge.ErrTooLarge
+			}
+		}
+	}
+	return apiErr
+}
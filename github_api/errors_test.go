@@ -0,0 +1,63 @@
+package github_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ghexplorer/forge"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/users/ghost":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found","documentation_url":"https://docs.github.com/rest/users/users#get-a-user"}`)
+		case "/api/v3/repos/acme/secret/contents":
+			w.Header().Set("X-GitHub-SSO", "required; url=https://github.com/orgs/acme/sso?authorization_request=1")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Resource protected by organization SAML enforcement."}`)
+		case "/api/v3/repos/acme/big/contents/data.bin":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"This API returns blobs up to 1 MB in size.","errors":[{"resource":"Blob","field":"data","code":"too_large"}]}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "secret")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetProfile(ctx, "ghost")
+	assert.ErrorIs(t, err, forge.ErrNotFound)
+	assert.Equal(t, "failed to fetch profile: 404 Not Found", err.Error())
+	var apiErr *forge.APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "https://docs.github.com/rest/users/users#get-a-user", apiErr.DocumentationURL)
+	}
+
+	_, err = client.ListContents(ctx, "acme", "secret", "", "")
+	assert.ErrorIs(t, err, forge.ErrForbidden)
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "https://github.com/orgs/acme/sso?authorization_request=1", apiErr.SSOURL)
+	}
+
+	_, err = client.GetFile(ctx, "acme", "big", "", "data.bin")
+	assert.ErrorIs(t, err, forge.ErrTooLarge)
+
+	_, err = client.ListRepositories(ctx, "someone")
+	assert.ErrorIs(t, err, forge.ErrUnauthorized)
+	assert.Contains(t, err.Error(), "Bad credentials")
+
+	server.Close()
+	_, err = client.GetProfile(ctx, "ghost")
+	assert.ErrorIs(t, err, forge.ErrNetwork)
+}
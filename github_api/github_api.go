@@ -1,44 +1,57 @@
 package github_api
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"ghexplorer/config"
+	"ghexplorer/forge"
 
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
-// GitHubProfile is GitHub profile struct
-type GitHubProfile struct {
-	Name        string `json:"name"`
-	Login       string `json:"login"`
-	Description string `json:"bio"`
-	Followers   int    `json:"followers"`
-	Following   int    `json:"following"`
-}
+// GetProfile fetch GitHub profile
+func (c *Client) GetProfile(ctx context.Context, username string) (*forge.Profile, error) {
+	customUrl := c.endpoint(nil, "users", username)
+	resp, err := c.get(ctx, customUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
 
-// Repository is GitHub profile repository struct
-type Repository struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-}
+		}
+	}(resp.Body)
 
-// FileInfo is GitHub profile repository file info struct
-type FileInfo struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
-}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp, "profile")
+	}
 
-// FetchGitHubProfile fetch GitHub profile
-func FetchGitHubProfile(username string) (*GitHubProfile, error) {
-	customUrl := fmt.Sprintf("%s/users/%s", config.GithubAPIBaseURL, username)
-	resp, err := http.Get(customUrl)
+	var profile forge.Profile
+	err = json.NewDecoder(resp.Body).Decode(&profile)
 	if err != nil {
 		return nil, err
 	}
+	return &profile, nil
+}
+
+// AuthenticatedUser fetch the login of the user owning the token
+func (c *Client) AuthenticatedUser(ctx context.Context) (string, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	if c.state.login != "" {
+		return c.state.login, nil
+	}
+
+	resp, err := c.get(ctx, c.endpoint(nil, "user"))
+	if err != nil {
+		return "", err
+	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
@@ -47,48 +60,58 @@ func FetchGitHubProfile(username string) (*GitHubProfile, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch profile: %s", resp.Status)
+		return "", apiError(resp, "authenticated user")
 	}
 
-	var profile GitHubProfile
+	var profile forge.Profile
 	err = json.NewDecoder(resp.Body).Decode(&profile)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	return &profile, nil
+	c.state.login = profile.Login
+	return c.state.login, nil
+}
+
+// repositoriesURL returns a page of the repositories listing endpoint for username.
+// The token owner's own listing includes private repositories.
+func (c *Client) repositoriesURL(ctx context.Context, username string, page, perPage int) string {
+	query := url.Values{
+		"page":     {strconv.Itoa(page)},
+		"per_page": {strconv.Itoa(perPage)},
+	}
+	if c.Authenticated() {
+		login, err := c.AuthenticatedUser(ctx)
+		if err == nil && strings.EqualFold(login, username) {
+			query.Set("affiliation", "owner")
+			return c.endpoint(query, "user", "repos")
+		}
+	}
+	return c.endpoint(query, "users", username, "repos")
 }
 
-// fetchProfileReadme fetches the user's profile README.md content
-// func fetchProfileReadme(username string) (string, error) {
-// 	content, err := fetchFileContent(username, username, "README.md")
-// 	if err != nil {
-// 		return "", err
-// 	}
-// 	return content, nil
-// }
-
-// FetchRepositories fetch GitHub profile repositories with pagination
-func FetchRepositories(username string) ([]*Repository, error) {
-	var allRepos []*Repository
+// ListRepositories fetch GitHub profile repositories with pagination
+func (c *Client) ListRepositories(ctx context.Context, username string) ([]*forge.Repository, error) {
+	var allRepos []*forge.Repository
 	page := 1
 	perPage := 100 // Maximum allowed by GitHub API
 
 	for {
-		customUrl := fmt.Sprintf("%s/users/%s/repos?page=%d&per_page=%d", config.GithubAPIBaseURL, username, page, perPage)
-		resp, err := http.Get(customUrl)
+		customUrl := c.repositoriesURL(ctx, username, page, perPage)
+		resp, err := c.get(ctx, customUrl)
 		if err != nil {
 			return nil, err
 		}
 
 		if resp.StatusCode != http.StatusOK {
+			apiErr := apiError(resp, "repositories")
 			err := resp.Body.Close()
 			if err != nil {
 				return nil, err
 			}
-			return nil, fmt.Errorf("failed to fetch repositories: %s", resp.Status)
+			return nil, apiErr
 		}
 
-		var repos []*Repository
+		var repos []*forge.Repository
 		err = json.NewDecoder(resp.Body).Decode(&repos)
 		errResp := resp.Body.Close()
 		if errResp != nil {
@@ -116,10 +139,10 @@ func FetchRepositories(username string) ([]*Repository, error) {
 	return allRepos, nil
 }
 
-// FetchRepositoryContents fetch GitHub profile repository contents
-func FetchRepositoryContents(username, repo, path string) ([]*FileInfo, error) {
-	customUrl := fmt.Sprintf("%s/repos/%s/%s/contents/%s", config.GithubAPIBaseURL, username, repo, path)
-	resp, err := http.Get(customUrl)
+// ListContents fetch GitHub profile repository contents
+func (c *Client) ListContents(ctx context.Context, username, repo, ref, path string) ([]*forge.FileInfo, error) {
+	customUrl := c.endpoint(refQuery(ref), "repos", username, repo, "contents", path)
+	resp, err := c.get(ctx, customUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -131,23 +154,36 @@ func FetchRepositoryContents(username, repo, path string) ([]*FileInfo, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch repository contents: %s", resp.Status)
+		return nil, apiError(resp, "repository contents")
 	}
 
-	var contents []*FileInfo
-	err = json.NewDecoder(resp.Body).Decode(&contents)
+	var entries []*struct {
+		forge.FileInfo
+		GitURL string `json:"git_url"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&entries)
 	if err != nil {
 		return nil, err
 	}
+
+	contents := make([]*forge.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		// For backwards compatibility, listings show submodules as files
+		// pointing at a tree of another repository
+		if entry.Type == "file" && entry.DownloadURL == "" && strings.Contains(entry.GitURL, "/git/trees/") {
+			entry.Type = "submodule"
+		}
+		contents = append(contents, &entry.FileInfo)
+	}
 	return contents, nil
 }
 
-// FetchFileContent fetch GitHub profile repository file contents
-func FetchFileContent(username, repo, path string) (string, error) {
-	customUrl := fmt.Sprintf("%s/repos/%s/%s/contents/%s", config.GithubAPIBaseURL, username, repo, path)
-	resp, err := http.Get(customUrl)
+// DescribeEntry completes an entry of a listing with the symlink target or
+// submodule URL, which listings omit, returned when fetching it alone
+func (c *Client) DescribeEntry(ctx context.Context, username, repo, ref string, entry *forge.FileInfo) (*forge.FileInfo, error) {
+	resp, err := c.get(ctx, c.endpoint(refQuery(ref), "repos", username, repo, "contents", entry.Path))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -157,32 +193,119 @@ func FetchFileContent(username, repo, path string) (string, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch file content: %s", resp.Status)
+		return nil, apiError(resp, entry.Name)
+	}
+	var details forge.FileInfo
+	err = json.NewDecoder(resp.Body).Decode(&details)
+	if err != nil {
+		return nil, err
+	}
+
+	described := *entry
+	switch {
+	case details.Type == "submodule":
+		described.Type = details.Type
+		described.SubmoduleGitURL = details.SubmoduleGitURL
+	case details.Type == "symlink":
+		described.Type = details.Type
+		described.Target = details.Target
+	case entry.Type == "symlink" && details.Path != "":
+		// Symlinks to files are answered with the file they point to
+		described.Target = "/" + details.Path
+	}
+	return &described, nil
+}
+
+// GetFile fetch GitHub profile repository file contents. Files over 1 MB, whose
+// content the contents API omits, are streamed with the raw media type.
+func (c *Client) GetFile(ctx context.Context, username, repo, ref, path string) (*forge.File, error) {
+	return c.getContent(ctx, c.endpoint(refQuery(ref), "repos", username, repo, "contents", path), "file content")
+}
+
+// GetReadme fetch the README GitHub shows for a repository, found at its root
+// or in its .github and docs directories
+func (c *Client) GetReadme(ctx context.Context, username, repo, ref string) (*forge.File, error) {
+	return c.getContent(ctx, c.endpoint(refQuery(ref), "repos", username, repo, "readme"), "README")
+}
+
+// getContent fetch a file from a contents endpoint, streaming the files too
+// large to be inlined
+func (c *Client) getContent(ctx context.Context, customUrl, what string) (*forge.File, error) {
+	resp, err := c.get(ctx, customUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp, what)
 	}
 
 	var fileContent struct {
+		Path     string `json:"path"`
+		SHA      string `json:"sha"`
+		Size     int64  `json:"size"`
 		Content  string `json:"content"`
 		Encoding string `json:"encoding"`
 	}
 	err = json.NewDecoder(resp.Body).Decode(&fileContent)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if fileContent.Encoding == "base64" {
+	file := &forge.File{
+		Path:     fileContent.Path,
+		SHA:      fileContent.SHA,
+		Size:     fileContent.Size,
+		Encoding: fileContent.Encoding,
+	}
+	switch {
+	case fileContent.Encoding == "none" || (fileContent.Content == "" && fileContent.Size > 0):
+		err = c.readRaw(ctx, customUrl, what, file)
+		if err != nil {
+			return nil, err
+		}
+	case fileContent.Encoding == "base64":
 		decodedContent, err := base64.StdEncoding.DecodeString(fileContent.Content)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		return string(decodedContent), nil
+		file.SetContent(decodedContent)
+	default:
+		file.SetContent([]byte(fileContent.Content))
+	}
+	return file, nil
+}
+
+// readRaw streams the content of a file with the raw media type
+func (c *Client) readRaw(ctx context.Context, customUrl, what string, file *forge.File) error {
+	resp, err := c.getMedia(ctx, customUrl, "application/vnd.github.raw")
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp, what)
 	}
-	return fileContent.Content, nil
+	file.Encoding = "raw"
+	return file.ReadContent(resp.Body)
 }
 
 // SearchRepositories perform searching through GitHub profile repositories
-func SearchRepositories(username, query string) ([]*Repository, error) {
-	customUrl := fmt.Sprintf("%s/search/repositories?q=%s+user:%s", config.GithubAPIBaseURL, url.QueryEscape(query), username)
-	resp, err := http.Get(customUrl)
+func (c *Client) SearchRepositories(ctx context.Context, username, query string) ([]*forge.Repository, error) {
+	customUrl := c.endpoint(url.Values{"q": {fmt.Sprintf("%s user:%s", query, username)}}, "search", "repositories")
+	resp, err := c.get(ctx, customUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -194,11 +317,11 @@ func SearchRepositories(username, query string) ([]*Repository, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to search repositories: %s", resp.Status)
+		return nil, apiError(resp, "search results")
 	}
 
 	var searchResult struct {
-		Items []*Repository `json:"items"`
+		Items []*forge.Repository `json:"items"`
 	}
 	err = json.NewDecoder(resp.Body).Decode(&searchResult)
 	if err != nil {
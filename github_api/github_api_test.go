@@ -1,7 +1,18 @@
 package github_api
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"ghexplorer/cache"
+	"ghexplorer/forge"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -9,25 +20,136 @@ import (
 // TestUsername is the GitHub username used for testing.
 const TestUsername = "octocat"
 
-func TestFetchGitHubProfile(t *testing.T) {
-	profile, err := FetchGitHubProfile(TestUsername)
+// fixtures maps API paths below /api/v3 to the JSON bodies served by the test server.
+var fixtures = map[string]string{
+	"/users/octocat":                               `{"login":"octocat","name":"The Octocat","bio":"","followers":10,"following":9}`,
+	"/users/octocat/repos":                         `[{"name":"Hello-World","description":"My first repository on GitHub!","stargazers_count":80,"forks_count":9,"watchers_count":80,"language":"C","topics":["octocat","api"],"license":{"key":"mit","name":"MIT License","spdx_id":"MIT"},"default_branch":"master","pushed_at":"2011-01-26T19:06:43Z","updated_at":"2011-01-26T19:14:43Z","size":108},{"name":"Spoon-Knife","description":"This repo is for demonstration purposes only."}]`,
+	"/repos/octocat/Hello-World/contents":          `[{"name":"README","type":"file"},{"name":"docs","type":"dir"}]`,
+	"/repos/octocat/Hello-World/contents/README":   fmt.Sprintf(`{"content":%q,"encoding":"base64"}`, base64.StdEncoding.EncodeToString([]byte("Hello World!\n"))),
+	"/search/repositories":                         `{"items":[{"name":"Hello-World","description":"My first repository on GitHub!"}]}`,
+	"/repos/octocat/Hello-World/branches":          `[{"name":"master","commit":{"sha":"7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"}}]`,
+	"/repos/octocat/Hello-World/tags":              `[{"name":"v1.0","commit":{"sha":"c5b97d5ae6c19d5c5df71a34c7fbeeda2479ccbc"}}]`,
+	"/repos/octocat/Hello-World/contents?ref=v1.0": `[{"name":"README","type":"file"}]`,
+	"/repos/octocat/Hello-World/readme":            fmt.Sprintf(`{"path":"README.md","sha":"b5d7","size":9,"content":%q,"encoding":"base64"}`, base64.StdEncoding.EncodeToString([]byte("# Hello\n\n"))),
+	"/repos/octocat/Hello-World/git/trees/HEAD":    `{"sha":"f0e1d2","tree":[{"path":"README","mode":"100644","type":"blob","sha":"980a0d5","size":13},{"path":"docs","mode":"040000","type":"tree","sha":"d0c5"},{"path":"docs/guide.md","mode":"100644","type":"blob","sha":"9d1c","size":7}],"truncated":false}`,
+	"/repos/octocat/Spoon-Knife/git/trees/HEAD":    `{"sha":"a1b2c3","tree":[{"path":"README.md","mode":"100644","type":"blob","sha":"3f94"}],"truncated":true}`,
+	"/repos/octocat/Spoon-Knife/git/trees/a1b2c3":  `{"sha":"a1b2c3","tree":[{"path":"README.md","mode":"100644","type":"blob","sha":"3f94"},{"path":"styles","mode":"040000","type":"tree","sha":"5e7a"}],"truncated":false}`,
+	"/repos/octocat/Spoon-Knife/git/trees/5e7a":    `{"sha":"5e7a","tree":[{"path":"main.css","mode":"100644","type":"blob","sha":"c55"}],"truncated":false}`,
+}
+
+// newTestClient starts a GitHub Enterprise Server stand-in serving fixtures
+// and returns a client pointed at it.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path[len("/api/v3"):]
+		if ref := r.URL.Query().Get("ref"); ref != "" {
+			key += "?ref=" + ref
+		}
+		body, ok := fixtures[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			body = "[]"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	return client
+}
+
+func TestParseBaseURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		api     string
+		web     string
+	}{
+		{"", "https://api.github.com", "https://github.com"},
+		{"github.com", "https://api.github.com", "https://github.com"},
+		{"https://api.github.com/", "https://api.github.com", "https://github.com"},
+		{"github.example.com", "https://github.example.com/api/v3", "https://github.example.com"},
+		{"https://github.example.com/api/v3/", "https://github.example.com/api/v3", "https://github.example.com"},
+		{"http://localhost:8080", "http://localhost:8080/api/v3", "http://localhost:8080"},
+	}
+	for _, tt := range tests {
+		api, web, err := parseBaseURL(tt.baseURL)
+		assert.NoError(t, err, tt.baseURL)
+		assert.Equal(t, tt.api, api.String(), tt.baseURL)
+		assert.Equal(t, tt.web, web.String(), tt.baseURL)
+	}
+
+	_, _, err := parseBaseURL("https://")
+	assert.Error(t, err)
+}
+
+func TestHTMLURLs(t *testing.T) {
+	client, err := NewClient("https://github.example.com", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/octocat/Hello-World/blob/HEAD/docs/my%20file.md",
+		client.FileHTMLURL(TestUsername, "Hello-World", "", "/docs/my file.md"))
+	assert.Equal(t, "https://github.example.com/octocat/Hello-World/blob/v1.0/README",
+		client.FileHTMLURL(TestUsername, "Hello-World", "v1.0", "README"))
+	assert.Equal(t, "https://github.example.com/search?q=hello+user%3Aoctocat&type=repositories",
+		client.SearchHTMLURL(TestUsername, "hello"))
+}
+
+func TestClientSendsToken(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "secret")
+	assert.NoError(t, err)
+	resp, err := client.get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Bearer secret", auth)
+
+	client, err = NewClient(server.URL, "")
+	assert.NoError(t, err)
+	resp, err = client.get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, auth)
+}
+
+func TestGetProfile(t *testing.T) {
+	profile, err := newTestClient(t).GetProfile(context.Background(), TestUsername)
 	assert.NoError(t, err)
 	assert.NotNil(t, profile)
 	assert.Equal(t, TestUsername, profile.Login)
 	assert.NotEmpty(t, profile.Name)
 }
 
-func TestFetchRepositories(t *testing.T) {
-	repos, err := FetchRepositories(TestUsername)
+func TestListRepositories(t *testing.T) {
+	repos, err := newTestClient(t).ListRepositories(context.Background(), TestUsername)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, repos)
 	for _, repo := range repos {
 		assert.NotEmpty(t, repo.Name)
 	}
+	assert.Equal(t, 80, repos[0].Stars)
+	assert.Equal(t, 9, repos[0].Forks)
+	assert.Equal(t, "C", repos[0].Language)
+	assert.Equal(t, []string{"octocat", "api"}, repos[0].Topics)
+	assert.Equal(t, "MIT", repos[0].LicenseName())
+	assert.Equal(t, "master", repos[0].DefaultBranch)
+	assert.Equal(t, 2011, repos[0].PushedAt.Year())
+	assert.Equal(t, int64(108), repos[0].Size)
 }
 
-func TestFetchRepositoryContents(t *testing.T) {
-	contents, err := FetchRepositoryContents(TestUsername, "Hello-World", "")
+func TestListContents(t *testing.T) {
+	contents, err := newTestClient(t).ListContents(context.Background(), TestUsername, "Hello-World", "", "")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, contents)
 	for _, item := range contents {
@@ -36,16 +158,254 @@ func TestFetchRepositoryContents(t *testing.T) {
 	}
 }
 
-func TestFetchFileContent(t *testing.T) {
-	content, err := FetchFileContent(TestUsername, "Hello-World", "README")
+func TestRefs(t *testing.T) {
+	client := newTestClient(t)
+	branches, err := client.ListBranches(context.Background(), TestUsername, "Hello-World")
+	assert.NoError(t, err)
+	assert.Equal(t, []*forge.Ref{{Name: "master", SHA: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"}}, branches)
+
+	tags, err := client.ListTags(context.Background(), TestUsername, "Hello-World")
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "v1.0", tags[0].Name)
+	}
+
+	contents, err := client.ListContents(context.Background(), TestUsername, "Hello-World", "v1.0", "")
 	assert.NoError(t, err)
-	assert.NotEmpty(t, content)
-	assert.Contains(t, content, "Hello World!")
+	assert.Len(t, contents, 1)
+}
+
+func TestGetTree(t *testing.T) {
+	client := newTestClient(t)
+	tree, err := client.GetTree(context.Background(), TestUsername, "Hello-World", "")
+	assert.NoError(t, err)
+	assert.False(t, tree.Truncated)
+	if assert.Len(t, tree.Entries, 3) {
+		assert.Equal(t, &forge.FileInfo{Name: "guide.md", Type: "file", Path: "docs/guide.md", SHA: "9d1c", Size: 7}, tree.Entries[2])
+	}
+
+	// A truncated recursive listing is walked one directory at a time
+	tree, err = client.GetTree(context.Background(), TestUsername, "Spoon-Knife", "")
+	assert.NoError(t, err)
+	assert.False(t, tree.Truncated)
+	var paths []string
+	for _, entry := range tree.Entries {
+		paths = append(paths, entry.Path)
+	}
+	assert.Equal(t, []string{"README.md", "styles", "styles/main.css"}, paths)
+
+	_, err = client.GetTree(context.Background(), TestUsername, "missing", "")
+	assert.ErrorIs(t, err, forge.ErrNotFound)
+}
+
+func TestGetTreeWalkLimits(t *testing.T) {
+	// Every directory holds the next one, and the directory failing is missing
+	failing := ""
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		sha := path.Base(r.URL.Path)
+		if r.URL.Query().Get("recursive") != "" {
+			fmt.Fprint(w, `{"sha":"d0","tree":[{"path":"d","mode":"040000","type":"tree","sha":"d1"}],"truncated":true}`)
+			return
+		}
+		if sha == failing {
+			http.NotFound(w, r)
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimPrefix(sha, "d"))
+		fmt.Fprintf(w, `{"sha":%q,"tree":[{"path":"d","mode":"040000","type":"tree","sha":"d%d"}],"truncated":false}`, sha, n+1)
+	}))
+	defer server.Close()
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+
+	tree, err := client.GetTree(context.Background(), TestUsername, "deep", "")
+	assert.NoError(t, err)
+	assert.True(t, tree.Truncated)
+	assert.Len(t, tree.Entries, maxSubtreeRequests)
+	assert.Equal(t, 1+maxSubtreeRequests, requests)
+
+	// What was walked before a request failed is kept
+	failing = "d3"
+	tree, err = client.GetTree(context.Background(), TestUsername, "deep", "")
+	assert.NoError(t, err)
+	assert.True(t, tree.Truncated)
+	if assert.Len(t, tree.Entries, 3) {
+		assert.Equal(t, "d/d/d", tree.Entries[2].Path)
+	}
+}
+
+func TestGetFile(t *testing.T) {
+	file, err := newTestClient(t).GetFile(context.Background(), TestUsername, "Hello-World", "", "README")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, file.Content)
+	assert.Contains(t, string(file.Content), "Hello World!")
+	assert.Equal(t, "base64", file.Encoding)
+	assert.False(t, file.Binary)
+}
+
+func TestGetReadme(t *testing.T) {
+	client := newTestClient(t)
+	readme, err := client.GetReadme(context.Background(), TestUsername, "Hello-World", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "README.md", readme.Path)
+	assert.Equal(t, "# Hello\n\n", string(readme.Content))
+
+	_, err = client.GetReadme(context.Background(), TestUsername, "Spoon-Knife", "")
+	assert.ErrorIs(t, err, forge.ErrNotFound)
+	assert.ErrorContains(t, err, "README")
+}
+
+func TestGetLargeFile(t *testing.T) {
+	data := append([]byte("\x89PNG\r\n\x1a\n\x00"), make([]byte, 2<<20)...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "application/vnd.github.raw" {
+			w.Write(data)
+			return
+		}
+		fmt.Fprintf(w, `{"path":"logo.png","sha":"3f2a9c1","size":%d,"content":"","encoding":"none"}`, len(data))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	file, err := client.GetFile(context.Background(), TestUsername, "Hello-World", "", "logo.png")
+	assert.NoError(t, err)
+	assert.Equal(t, "raw", file.Encoding)
+	assert.Equal(t, "3f2a9c1", file.SHA)
+	assert.Equal(t, int64(len(data)), file.Size)
+	assert.Len(t, file.Content, len(data))
+	assert.True(t, file.Binary)
+	assert.False(t, file.Truncated)
+
+	// Large files are cached too, and open offline
+	store, err := cache.Open(t.TempDir())
+	assert.NoError(t, err)
+	client.SetCache(store, time.Hour)
+	_, err = client.GetFile(context.Background(), TestUsername, "Hello-World", "", "logo.png")
+	assert.NoError(t, err)
+	client.SetOffline(true)
+	file, err = client.GetFile(context.Background(), TestUsername, "Hello-World", "", "logo.png")
+	assert.NoError(t, err)
+	assert.Equal(t, "raw", file.Encoding)
+	assert.Equal(t, data, file.Content)
 }
 
 func TestSearchRepositories(t *testing.T) {
-	repos, err := SearchRepositories(TestUsername, "Hello-World")
+	repos, err := newTestClient(t).SearchRepositories(context.Background(), TestUsername, "Hello-World")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, repos)
 	assert.Contains(t, repos[0].Name, "Hello-World")
 }
+
+func TestCachedRequests(t *testing.T) {
+	calls, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"login":"octocat","name":"The Octocat"}`)
+	}))
+	defer server.Close()
+
+	store, err := cache.Open(t.TempDir())
+	assert.NoError(t, err)
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+
+	// Fresh entries are served without a request
+	client.SetCache(store, time.Hour)
+	for i := 0; i < 2; i++ {
+		profile, err := client.GetProfile(context.Background(), TestUsername)
+		assert.NoError(t, err)
+		assert.Equal(t, "The Octocat", profile.Name)
+	}
+	assert.Equal(t, 1, calls)
+
+	// Expired entries are revalidated
+	client.SetCache(store, 0)
+	profile, err := client.GetProfile(context.Background(), TestUsername)
+	assert.NoError(t, err)
+	assert.Equal(t, "The Octocat", profile.Name)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, notModified)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	client.SetTimeout(20 * time.Millisecond)
+
+	_, err = client.GetProfile(context.Background(), TestUsername)
+	assert.Error(t, err)
+
+	client.SetTimeout(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetProfile(ctx, TestUsername)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestListContentsDetails(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/api/v3/repos/octocat/Hello-World/contents":
+			fmt.Fprint(w, `[
+				{"name":"README","path":"README","type":"file","size":13,"sha":"980a0d5","download_url":"https://raw.example/README","git_url":"https://api.example/git/blobs/980a0d5"},
+				{"name":"docs-link","path":"docs-link","type":"symlink","size":4,"sha":"1a2b3c4","download_url":"https://raw.example/docs-link"},
+				{"name":"readme-link","path":"readme-link","type":"symlink","size":6,"sha":"5d6e7f8","download_url":"https://raw.example/readme-link"},
+				{"name":"lib","path":"lib","type":"file","size":0,"sha":"fa11ed0","download_url":null,"git_url":"https://api.example/repos/octocat/Spoon-Knife/git/trees/fa11ed0"}
+			]`)
+		case "/api/v3/repos/octocat/Hello-World/contents/docs-link":
+			fmt.Fprint(w, `{"name":"docs-link","path":"docs-link","type":"symlink","target":"docs"}`)
+		case "/api/v3/repos/octocat/Hello-World/contents/readme-link":
+			fmt.Fprint(w, `{"name":"README","path":"README","type":"file","content":""}`)
+		case "/api/v3/repos/octocat/Hello-World/contents/lib":
+			fmt.Fprint(w, `{"name":"lib","path":"lib","type":"submodule","submodule_git_url":"git://github.com/octocat/Spoon-Knife.git"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	contents, err := client.ListContents(context.Background(), TestUsername, "Hello-World", "", "")
+	assert.NoError(t, err)
+	if !assert.Len(t, contents, 4) {
+		return
+	}
+	assert.Equal(t, int64(13), contents[0].Size)
+	assert.Equal(t, "980a0d5", contents[0].SHA)
+	assert.Equal(t, "https://raw.example/README", contents[0].DownloadURL)
+	assert.Equal(t, "submodule", contents[3].Type)
+	assert.Equal(t, 1, requests)
+
+	// Symlink targets and submodule URLs are fetched for one entry at a time
+	want := map[string]string{"docs-link": "docs", "readme-link": "/README", "lib": "git://github.com/octocat/Spoon-Knife.git"}
+	for _, entry := range contents[1:] {
+		described, err := client.DescribeEntry(context.Background(), TestUsername, "Hello-World", "", entry)
+		assert.NoError(t, err)
+		assert.Equal(t, want[entry.Name], described.Target+described.SubmoduleGitURL, entry.Name)
+		assert.Empty(t, entry.Target+entry.SubmoduleGitURL, entry.Name)
+	}
+	assert.Equal(t, 4, requests)
+
+	_, err = client.DescribeEntry(context.Background(), TestUsername, "Hello-World", "", &forge.FileInfo{Name: "gone", Path: "gone", Type: "symlink"})
+	assert.ErrorIs(t, err, forge.ErrNotFound)
+}
@@ -0,0 +1,24 @@
+package github_api
+
+import (
+	"ghexplorer/forge"
+)
+
+// SetOffline makes the client answer from the cache only, regardless of age.
+// Responses that were never cached fail with forge.ErrNotCached.
+func (c *Client) SetOffline(offline bool) {
+	c.offline = offline
+}
+
+// Offline reports whether the client is in offline mode
+func (c *Client) Offline() bool {
+	return c.offline
+}
+
+// Traced returns a client sharing c's configuration and state that reports the
+// cached responses it serves to trace
+func (c *Client) Traced(trace *forge.CacheTrace) forge.Provider {
+	traced := *c
+	traced.trace = trace
+	return &traced
+}
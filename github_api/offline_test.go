@@ -0,0 +1,49 @@
+package github_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ghexplorer/cache"
+	"ghexplorer/forge"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffline(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"login":"octocat","name":"The Octocat"}`)
+	}))
+	defer server.Close()
+
+	store, err := cache.Open(t.TempDir())
+	assert.NoError(t, err)
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	client.SetCache(store, time.Minute)
+
+	before := time.Now().Add(-time.Second)
+	_, err = client.GetProfile(context.Background(), TestUsername)
+	assert.NoError(t, err)
+
+	client.SetOffline(true)
+	trace := &forge.CacheTrace{}
+	profile, err := client.Traced(trace).GetProfile(context.Background(), TestUsername)
+	assert.NoError(t, err)
+	assert.Equal(t, "The Octocat", profile.Name)
+	assert.Equal(t, 1, calls)
+
+	storedAt, ok := trace.Oldest()
+	assert.True(t, ok)
+	assert.True(t, storedAt.After(before))
+
+	_, err = client.ListContents(context.Background(), TestUsername, "Hello-World", "", "")
+	assert.True(t, errors.Is(err, forge.ErrNotCached))
+	assert.Equal(t, 1, calls)
+}
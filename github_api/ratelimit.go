@@ -0,0 +1,186 @@
+package github_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"ghexplorer/forge"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSecondaryWait is how long to back off when a secondary rate limit
+// response carries no Retry-After header
+const defaultSecondaryWait = time.Minute
+
+// maxSecondaryRetries bounds how many times a request is retried after a secondary rate limit
+const maxSecondaryRetries = 3
+
+// RateLimitError is returned when the API refuses a request because a rate limit was hit
+type RateLimitError struct {
+	RateLimit forge.RateLimit
+	// Secondary is set for abuse-detection limits, which are lifted after RetryAfter
+	Secondary        bool
+	RetryAfter       time.Duration
+	Message          string
+	DocumentationURL string
+}
+
+// Is makes rate limit errors match forge.ErrRateLimited
+func (e *RateLimitError) Is(target error) bool {
+	return target == forge.ErrRateLimited
+}
+
+// Error describes the limit and when requests can be made again
+func (e *RateLimitError) Error() string {
+	if e.Secondary {
+		return fmt.Sprintf("secondary rate limit exceeded, retry in %s: %s", e.RetryAfter.Round(time.Second), e.Message)
+	}
+	wait := time.Until(e.RateLimit.Reset).Round(time.Minute)
+	if wait < 0 {
+		wait = 0
+	}
+	return fmt.Sprintf("API rate limit exceeded (%d requests per hour), resets at %s (in %s): %s",
+		e.RateLimit.Limit, e.RateLimit.Reset.Local().Format("15:04"), wait, e.Message)
+}
+
+// parseRateLimit reads the X-RateLimit-* response headers
+func parseRateLimit(header http.Header) (forge.RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return forge.RateLimit{}, false
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(header.Get("X-RateLimit-Used"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	return forge.RateLimit{
+		Resource:  header.Get("X-RateLimit-Resource"),
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+		Reset:     time.Unix(reset, 0),
+	}, true
+}
+
+// maxRateLimitBody bounds how much of a refused response is read to find out
+// whether a rate limit was hit
+const maxRateLimitBody = 64 << 10
+
+// checkRateLimit turns a rate limited response into a RateLimitError, consuming
+// its body. Refusals are rate limits when their headers or their message say so:
+// secondary rate limits may leave quota remaining and carry no Retry-After. It
+// returns nil for any other response, whose body is left to read.
+func checkRateLimit(resp *http.Response) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	rate, hasRate := parseRateLimit(resp.Header)
+	retryAfter := resp.Header.Get("Retry-After")
+
+	var body struct {
+		Message          string `json:"message"`
+		DocumentationURL string `json:"documentation_url"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxRateLimitBody))
+	_ = json.Unmarshal(data, &body)
+	message := strings.ToLower(body.Message)
+	secondary := retryAfter != "" || strings.Contains(message, "secondary rate limit")
+	if !secondary && !(hasRate && rate.Remaining == 0) && !strings.Contains(message, "rate limit exceeded") {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		return nil
+	}
+	if body.Message == "" {
+		body.Message = resp.Status
+	}
+
+	rateErr := &RateLimitError{RateLimit: rate, Message: body.Message, DocumentationURL: body.DocumentationURL}
+	if secondary {
+		rateErr.Secondary = true
+		rateErr.RetryAfter = defaultSecondaryWait
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			rateErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return rateErr
+}
+
+// RateLimit returns the quota reported by the most recent response
+func (c *Client) RateLimit() (forge.RateLimit, bool) {
+	c.state.rateMu.Lock()
+	defer c.state.rateMu.Unlock()
+	return c.state.rate, c.state.hasRate
+}
+
+// recordRateLimit remembers the quota reported by a response
+func (c *Client) recordRateLimit(header http.Header) {
+	rate, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+	c.state.rateMu.Lock()
+	defer c.state.rateMu.Unlock()
+	c.state.rate = rate
+	c.state.hasRate = true
+}
+
+// SetSecondaryRateLimitWait makes the client sleep and retry requests hitting a
+// secondary rate limit, as long as the requested delay does not exceed maxWait.
+// A zero maxWait fails immediately with a RateLimitError.
+func (c *Client) SetSecondaryRateLimitWait(maxWait time.Duration) {
+	c.maxRetryWait = maxWait
+}
+
+// FetchRateLimits fetch the quota of every API resource
+func (c *Client) FetchRateLimits(ctx context.Context) ([]forge.RateLimit, error) {
+	resp, err := c.send(ctx, c.endpoint(nil, "rate_limit"), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("rate limiting is not enabled on %s", c.BaseURL())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp, "rate limit")
+	}
+
+	var result struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Used      int   `json:"used"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make([]forge.RateLimit, 0, len(result.Resources))
+	for name, resource := range result.Resources {
+		limits = append(limits, forge.RateLimit{
+			Resource:  name,
+			Limit:     resource.Limit,
+			Remaining: resource.Remaining,
+			Used:      resource.Used,
+			Reset:     time.Unix(resource.Reset, 0),
+		})
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Resource < limits[j].Resource })
+	return limits, nil
+}
@@ -0,0 +1,126 @@
+package github_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ghexplorer/forge"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitError(t *testing.T) {
+	reset := time.Now().Add(30 * time.Minute).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset))
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"API rate limit exceeded for 127.0.0.1."}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	_, err = client.GetProfile(context.Background(), TestUsername)
+
+	var rateErr *RateLimitError
+	assert.True(t, errors.As(err, &rateErr))
+	assert.False(t, rateErr.Secondary)
+	assert.Equal(t, 60, rateErr.RateLimit.Limit)
+	assert.Equal(t, reset, rateErr.RateLimit.Reset.Unix())
+	assert.Contains(t, err.Error(), "API rate limit exceeded for 127.0.0.1.")
+	assert.ErrorIs(t, err, forge.ErrRateLimited)
+
+	rate, ok := client.RateLimit()
+	assert.True(t, ok)
+	assert.Equal(t, 0, rate.Remaining)
+}
+
+func TestSecondaryRateLimitRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit."}`)
+			return
+		}
+		fmt.Fprint(w, `{"login":"octocat"}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+
+	_, err = client.GetProfile(context.Background(), TestUsername)
+	var rateErr *RateLimitError
+	assert.True(t, errors.As(err, &rateErr))
+	assert.True(t, rateErr.Secondary)
+	assert.Equal(t, time.Duration(0), rateErr.RetryAfter)
+
+	calls = 0
+	client.SetSecondaryRateLimitWait(time.Second)
+	profile, err := client.GetProfile(context.Background(), TestUsername)
+	assert.NoError(t, err)
+	assert.Equal(t, TestUsername, profile.Login)
+	assert.Equal(t, 2, calls)
+}
+
+func TestForbiddenIsNotRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	_, err = client.GetProfile(context.Background(), TestUsername)
+
+	var rateErr *RateLimitError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &rateErr))
+}
+
+func TestSecondaryRateLimitWithQuotaLeft(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	_, err = client.GetProfile(context.Background(), TestUsername)
+
+	var rateErr *RateLimitError
+	if assert.True(t, errors.As(err, &rateErr)) {
+		assert.True(t, rateErr.Secondary)
+		assert.Equal(t, defaultSecondaryWait, rateErr.RetryAfter)
+	}
+}
+
+func TestForbiddenMessageIsKept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"Resource not accessible by integration"}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	assert.NoError(t, err)
+	_, err = client.GetProfile(context.Background(), TestUsername)
+	assert.ErrorContains(t, err, "Resource not accessible by integration")
+	assert.NotErrorIs(t, err, forge.ErrRateLimited)
+}
@@ -0,0 +1,85 @@
+package github_api
+
+import (
+	"context"
+	"encoding/json"
+	"ghexplorer/forge"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// refQuery returns the query selecting ref, none for the default branch
+func refQuery(ref string) url.Values {
+	if ref == "" {
+		return nil
+	}
+	return url.Values{"ref": {ref}}
+}
+
+// ListBranches fetch GitHub repository branches with pagination
+func (c *Client) ListBranches(ctx context.Context, username, repo string) ([]*forge.Ref, error) {
+	return c.listRefs(ctx, username, repo, "branches")
+}
+
+// ListTags fetch GitHub repository tags with pagination
+func (c *Client) ListTags(ctx context.Context, username, repo string) ([]*forge.Ref, error) {
+	return c.listRefs(ctx, username, repo, "tags")
+}
+
+// listRefs fetch the branches or tags of a repository, which are listed alike
+func (c *Client) listRefs(ctx context.Context, username, repo, kind string) ([]*forge.Ref, error) {
+	var refs []*forge.Ref
+	perPage := 100 // Maximum allowed by GitHub API
+
+	for page := 1; ; page++ {
+		customUrl := c.endpoint(url.Values{
+			"page":     {strconv.Itoa(page)},
+			"per_page": {strconv.Itoa(perPage)},
+		}, "repos", username, repo, kind)
+		batch, err := c.fetchRefs(ctx, customUrl, kind)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, batch...)
+		if len(batch) < perPage {
+			return refs, nil
+		}
+	}
+}
+
+// fetchRefs fetch a page of branches or tags
+func (c *Client) fetchRefs(ctx context.Context, customUrl, kind string) ([]*forge.Ref, error) {
+	resp, err := c.get(ctx, customUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp, kind)
+	}
+
+	var entries []struct {
+		Name   string `json:"name"`
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&entries)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*forge.Ref, 0, len(entries))
+	for _, entry := range entries {
+		refs = append(refs, &forge.Ref{Name: entry.Name, SHA: entry.Commit.SHA})
+	}
+	return refs, nil
+}
@@ -0,0 +1,130 @@
+package github_api
+
+import (
+	"context"
+	"encoding/json"
+	"ghexplorer/forge"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// gitTree is the Git Trees API payload
+type gitTree struct {
+	SHA  string `json:"sha"`
+	Tree []struct {
+		Path string `json:"path"`
+		Mode string `json:"mode"`
+		Type string `json:"type"`
+		SHA  string `json:"sha"`
+		Size int64  `json:"size"`
+	} `json:"tree"`
+	Truncated bool `json:"truncated"`
+}
+
+// maxSubtreeRequests bounds the requests made to walk a tree too large for a
+// recursive listing
+const maxSubtreeRequests = 50
+
+// GetTree fetch the whole GitHub repository tree at once. Trees too large for
+// a recursive listing are walked one directory at a time, up to
+// maxSubtreeRequests directories.
+func (c *Client) GetTree(ctx context.Context, username, repo, ref string) (*forge.Tree, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	root, err := c.fetchTree(ctx, username, repo, ref, true)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &forge.Tree{}
+	if !root.Truncated {
+		addEntries(tree, root, "")
+		return tree, nil
+	}
+	if err := c.walkTree(ctx, username, repo, root.SHA, tree); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		tree.Truncated = true
+	}
+	// A walk cut short may list less than the truncated recursive listing
+	if tree.Truncated && len(tree.Entries) < len(root.Tree) {
+		tree = &forge.Tree{}
+		addEntries(tree, root, "")
+	}
+	return tree, nil
+}
+
+// walkTree adds the entries of the tree sha and of its subtrees, level by
+// level. The tree is marked truncated when the walk stops at
+// maxSubtreeRequests, and what was fetched is kept when a request fails.
+func (c *Client) walkTree(ctx context.Context, username, repo, sha string, tree *forge.Tree) error {
+	type subtree struct{ sha, dir string }
+	queue := []subtree{{sha: sha}}
+	for requests := 0; len(queue) > 0; requests++ {
+		if requests == maxSubtreeRequests {
+			tree.Truncated = true
+			return nil
+		}
+		next := queue[0]
+		queue = queue[1:]
+		level, err := c.fetchTree(ctx, username, repo, next.sha, false)
+		if err != nil {
+			return err
+		}
+		addEntries(tree, level, next.dir)
+		for _, entry := range level.Tree {
+			if entry.Type == "tree" {
+				queue = append(queue, subtree{sha: entry.SHA, dir: path.Join(next.dir, entry.Path)})
+			}
+		}
+	}
+	return nil
+}
+
+// addEntries converts the entries of a tree found at dir
+func addEntries(tree *forge.Tree, level *gitTree, dir string) {
+	tree.Truncated = tree.Truncated || level.Truncated
+	for _, entry := range level.Tree {
+		info := &forge.FileInfo{
+			Name: path.Base(entry.Path),
+			Type: forge.EntryType(entry.Mode, entry.Type),
+			Path: path.Join(dir, entry.Path),
+			SHA:  entry.SHA,
+			Size: entry.Size,
+		}
+		tree.Entries = append(tree.Entries, info)
+	}
+}
+
+// fetchTree fetch a tree by SHA or ref, with its subtrees when recursive
+func (c *Client) fetchTree(ctx context.Context, username, repo, sha string, recursive bool) (*gitTree, error) {
+	var query url.Values
+	if recursive {
+		query = url.Values{"recursive": {"1"}}
+	}
+	resp, err := c.get(ctx, c.endpoint(query, "repos", username, repo, "git", "trees", sha))
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp, "repository tree")
+	}
+
+	var tree gitTree
+	err = json.NewDecoder(resp.Body).Decode(&tree)
+	if err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
@@ -0,0 +1,505 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"ghexplorer/forge"
+	"ghexplorer/helper"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the public GitLab instance
+const DefaultBaseURL = "https://gitlab.com"
+
+// perPage is the number of items requested per page, the maximum allowed by GitLab
+const perPage = 100
+
+// Client is a GitLab API client. GitLab users and groups are browsed as profiles,
+// their projects as repositories and the repository tree as files.
+type Client struct {
+	httpClient *http.Client
+	token      string
+	baseURL    *url.URL
+	webURL     *url.URL
+
+	mu         sync.Mutex
+	namespaces map[string]*namespace
+	branches   map[string]string
+}
+
+var _ forge.Provider = (*Client)(nil)
+
+// namespace is a resolved user or group
+type namespace struct {
+	ID      int64
+	Path    string
+	IsGroup bool
+}
+
+// project is the GitLab project payload
+type project struct {
+	Name              string    `json:"name"`
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	Description       string    `json:"description"`
+	DefaultBranch     string    `json:"default_branch"`
+	StarCount         int       `json:"star_count"`
+	ForksCount        int       `json:"forks_count"`
+	Topics            []string  `json:"topics"`
+	Archived          bool      `json:"archived"`
+	Visibility        string    `json:"visibility"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+	ForkedFromProject *struct {
+		ID int `json:"id"`
+	} `json:"forked_from_project"`
+}
+
+// treeEntry is the GitLab repository tree payload
+type treeEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+}
+
+// NewClient creates a client for the GitLab instance at baseURL sending the given
+// access token. An empty baseURL targets gitlab.com. The API is served below /api/v4.
+func NewClient(baseURL, token string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	path = strings.TrimSuffix(path, "/api/v4")
+	return &Client{
+		httpClient: http.DefaultClient,
+		token:      token,
+		baseURL:    &url.URL{Scheme: u.Scheme, Host: u.Host, Path: path + "/api/v4"},
+		webURL:     &url.URL{Scheme: u.Scheme, Host: u.Host, Path: path},
+		namespaces: make(map[string]*namespace),
+		branches:   make(map[string]string),
+	}, nil
+}
+
+// projectEndpoint builds the URL of a project resource. The project path and the
+// segments are escaped whole, slashes included, as GitLab expects.
+func (c *Client) projectEndpoint(project string, query url.Values, segments ...string) string {
+	var b strings.Builder
+	b.WriteString(helper.JoinURL(c.baseURL, nil, "projects"))
+	b.WriteString("/")
+	b.WriteString(url.PathEscape(project))
+	for _, segment := range segments {
+		b.WriteString("/")
+		b.WriteString(url.PathEscape(segment))
+	}
+	if len(query) > 0 {
+		b.WriteString("?")
+		b.WriteString(query.Encode())
+	}
+	return b.String()
+}
+
+// SetTimeout bounds each request, including reading its response. Zero disables the timeout.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.httpClient = &http.Client{Timeout: timeout}
+}
+
+// do performs an authenticated GET request
+func (c *Client) do(ctx context.Context, customUrl, what string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, customUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, forge.NetworkError(err, what)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := forge.ResponseError(resp, what)
+		resp.Body.Close()
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+// get performs an authenticated GET request and decodes the JSON response into v
+func (c *Client) get(ctx context.Context, customUrl, what string, v any) error {
+	resp, err := c.do(ctx, customUrl, what)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// fetchNamespace resolves username to a user or, failing that, a group
+func (c *Client) fetchNamespace(ctx context.Context, username string) (*namespace, *forge.Profile, error) {
+	var users []struct {
+		ID int64 `json:"id"`
+	}
+	err := c.get(ctx, helper.JoinURL(c.baseURL, url.Values{"username": {username}}, "users"), "profile", &users)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(users) > 0 {
+		var user struct {
+			Username  string `json:"username"`
+			Name      string `json:"name"`
+			Bio       string `json:"bio"`
+			Followers int    `json:"followers"`
+			Following int    `json:"following"`
+		}
+		err := c.get(ctx, helper.JoinURL(c.baseURL, nil, "users", strconv.FormatInt(users[0].ID, 10)), "profile", &user)
+		if err != nil {
+			return nil, nil, err
+		}
+		ns := &namespace{ID: users[0].ID, Path: user.Username}
+		profile := &forge.Profile{
+			Name:        user.Name,
+			Login:       user.Username,
+			Description: user.Bio,
+			Followers:   user.Followers,
+			Following:   user.Following,
+		}
+		return ns, profile, nil
+	}
+
+	var group struct {
+		ID          int64  `json:"id"`
+		Name        string `json:"name"`
+		FullPath    string `json:"full_path"`
+		Description string `json:"description"`
+	}
+	err = c.get(ctx, helper.JoinURL(c.baseURL, nil, "groups")+"/"+url.PathEscape(username), "profile", &group)
+	if err != nil {
+		return nil, nil, err
+	}
+	ns := &namespace{ID: group.ID, Path: group.FullPath, IsGroup: true}
+	profile := &forge.Profile{
+		Name:        group.Name,
+		Login:       group.FullPath,
+		Description: group.Description,
+	}
+	return ns, profile, nil
+}
+
+// namespace returns the cached user or group named username, resolving it if needed
+func (c *Client) namespace(ctx context.Context, username string) (*namespace, error) {
+	c.mu.Lock()
+	ns, ok := c.namespaces[username]
+	c.mu.Unlock()
+	if ok {
+		return ns, nil
+	}
+
+	ns, _, err := c.fetchNamespace(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.namespaces[username] = ns
+	c.mu.Unlock()
+	return ns, nil
+}
+
+// GetProfile fetch GitLab user or group profile
+func (c *Client) GetProfile(ctx context.Context, username string) (*forge.Profile, error) {
+	ns, profile, err := c.fetchNamespace(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.namespaces[username] = ns
+	c.mu.Unlock()
+	return profile, nil
+}
+
+// listProjects fetch the projects of username with pagination, optionally filtered by search
+func (c *Client) listProjects(ctx context.Context, username, search string) ([]*forge.Repository, error) {
+	ns, err := c.namespace(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := "users"
+	query := url.Values{"per_page": {strconv.Itoa(perPage)}}
+	if ns.IsGroup {
+		kind = "groups"
+		query.Set("include_subgroups", "true")
+	}
+	if search != "" {
+		query.Set("search", search)
+	}
+
+	var projects []*project
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var batch []*project
+		err := c.get(ctx, helper.JoinURL(c.baseURL, query, kind, strconv.FormatInt(ns.ID, 10), "projects"), "repositories", &batch)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, batch...)
+		if len(batch) < perPage {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	repos := make([]*forge.Repository, 0, len(projects))
+	for _, p := range projects {
+		c.branches[p.PathWithNamespace] = p.DefaultBranch
+		repo := &forge.Repository{
+			Name:          p.Name,
+			Description:   p.Description,
+			Stars:         p.StarCount,
+			Forks:         p.ForksCount,
+			Topics:        p.Topics,
+			Archived:      p.Archived,
+			Fork:          p.ForkedFromProject != nil,
+			Private:       p.Visibility == "private",
+			DefaultBranch: p.DefaultBranch,
+			UpdatedAt:     p.LastActivityAt,
+		}
+		// Projects are addressed by their path below the profile namespace
+		if path := strings.TrimPrefix(p.PathWithNamespace, ns.Path+"/"); path != p.Name {
+			repo.Path = path
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// ListRepositories fetch GitLab user or group projects with pagination
+func (c *Client) ListRepositories(ctx context.Context, username string) ([]*forge.Repository, error) {
+	return c.listProjects(ctx, username, "")
+}
+
+// SearchRepositories perform searching through GitLab user or group projects
+func (c *Client) SearchRepositories(ctx context.Context, username, query string) ([]*forge.Repository, error) {
+	return c.listProjects(ctx, username, query)
+}
+
+// projectPath returns the full path of owner's repo
+func projectPath(owner, repo string) string {
+	return owner + "/" + repo
+}
+
+// ref returns ref, or when empty the default branch of a project or HEAD when
+// it is unknown
+func (c *Client) ref(project, ref string) string {
+	if ref != "" {
+		return ref
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if branch := c.branches[project]; branch != "" {
+		return branch
+	}
+	return "HEAD"
+}
+
+// ListBranches fetch GitLab project branches with pagination
+func (c *Client) ListBranches(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	return c.listRefs(ctx, owner, repo, "branches")
+}
+
+// ListTags fetch GitLab project tags with pagination
+func (c *Client) ListTags(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	return c.listRefs(ctx, owner, repo, "tags")
+}
+
+// listRefs fetch the branches or tags of a project, which are listed alike
+func (c *Client) listRefs(ctx context.Context, owner, repo, kind string) ([]*forge.Ref, error) {
+	query := url.Values{"per_page": {strconv.Itoa(perPage)}}
+	var refs []*forge.Ref
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var entries []struct {
+			Name   string `json:"name"`
+			Commit struct {
+				ID string `json:"id"`
+			} `json:"commit"`
+		}
+		err := c.get(ctx, c.projectEndpoint(projectPath(owner, repo), query, "repository", kind), kind, &entries)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			refs = append(refs, &forge.Ref{Name: entry.Name, SHA: entry.Commit.ID})
+		}
+		if len(entries) < perPage {
+			return refs, nil
+		}
+	}
+}
+
+// ListContents fetch GitLab project repository tree with pagination
+func (c *Client) ListContents(ctx context.Context, owner, repo, ref, path string) ([]*forge.FileInfo, error) {
+	query := url.Values{"per_page": {strconv.Itoa(perPage)}}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+	if path = strings.Trim(path, "/"); path != "" {
+		query.Set("path", path)
+	}
+
+	var contents []*forge.FileInfo
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var entries []*treeEntry
+		err := c.get(ctx, c.projectEndpoint(projectPath(owner, repo), query, "repository", "tree"), "repository contents", &entries)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			contents = append(contents, c.fileInfo(owner, repo, ref, entry))
+		}
+		if len(entries) < perPage {
+			break
+		}
+	}
+	c.describe(ctx, owner, repo, ref, contents)
+	return contents, nil
+}
+
+// GetTree fetch the whole GitLab project repository tree with pagination
+func (c *Client) GetTree(ctx context.Context, owner, repo, ref string) (*forge.Tree, error) {
+	query := url.Values{"recursive": {"true"}, "per_page": {strconv.Itoa(perPage)}}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+
+	tree := &forge.Tree{}
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var entries []*treeEntry
+		err := c.get(ctx, c.projectEndpoint(projectPath(owner, repo), query, "repository", "tree"), "repository tree", &entries)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			tree.Entries = append(tree.Entries, c.fileInfo(owner, repo, ref, entry))
+		}
+		if len(entries) < perPage {
+			return tree, nil
+		}
+	}
+}
+
+// fileInfo converts a tree entry of owner's repo at ref
+func (c *Client) fileInfo(owner, repo, ref string, entry *treeEntry) *forge.FileInfo {
+	project := projectPath(owner, repo)
+	info := &forge.FileInfo{Name: entry.Name, Type: forge.EntryType(entry.Mode, entry.Type), Path: entry.Path, SHA: entry.ID}
+	switch info.Type {
+	case "dir":
+		info.HTMLURL = helper.JoinURL(c.webURL, nil, project, "-", "tree", c.ref(project, ref), entry.Path)
+	case "file", "symlink":
+		info.HTMLURL = c.FileHTMLURL(owner, repo, ref, entry.Path)
+		info.DownloadURL = helper.JoinURL(c.webURL, nil, project, "-", "raw", c.ref(project, ref), entry.Path)
+		// The tree API reports no sizes
+		info.SizeUnknown = true
+	}
+	return info
+}
+
+// describe completes symlinks with their target and submodules with the URL
+// declared in .gitmodules, which the tree API does not report. Entries whose
+// details cannot be fetched are left unchanged.
+func (c *Client) describe(ctx context.Context, owner, repo, ref string, contents []*forge.FileInfo) {
+	var gitmodules map[string]string
+	for _, entry := range contents {
+		switch entry.Type {
+		case "symlink":
+			if target, err := c.GetFile(ctx, owner, repo, ref, entry.Path); err == nil {
+				entry.Target = string(target.Content)
+			}
+		case "submodule":
+			if gitmodules == nil {
+				gitmodules = make(map[string]string)
+				if file, err := c.GetFile(ctx, owner, repo, ref, ".gitmodules"); err == nil {
+					gitmodules = forge.ParseGitmodules(string(file.Content))
+				}
+			}
+			entry.SubmoduleGitURL = gitmodules[entry.Path]
+		}
+	}
+}
+
+// GetReadme fetch the README at the root of a GitLab project repository
+func (c *Client) GetReadme(ctx context.Context, owner, repo, ref string) (*forge.File, error) {
+	return forge.ReadmeOf(ctx, c, owner, repo, ref)
+}
+
+// GetFile fetch GitLab project raw file contents
+func (c *Client) GetFile(ctx context.Context, owner, repo, ref, path string) (*forge.File, error) {
+	project := projectPath(owner, repo)
+	query := url.Values{"ref": {c.ref(project, ref)}}
+	resp, err := c.do(ctx, c.projectEndpoint(project, query, "repository", "files", strings.Trim(path, "/"), "raw"), "file content")
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	file := &forge.File{
+		Path:     strings.Trim(path, "/"),
+		SHA:      resp.Header.Get("X-Gitlab-Blob-Id"),
+		Size:     resp.ContentLength,
+		Encoding: "raw",
+	}
+	if size, err := strconv.ParseInt(resp.Header.Get("X-Gitlab-Size"), 10, 64); err == nil {
+		file.Size = size
+	}
+	err = file.ReadContent(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// FileHTMLURL returns the web page of a file at ref, the default branch when empty
+func (c *Client) FileHTMLURL(owner, repo, ref, path string) string {
+	project := projectPath(owner, repo)
+	return helper.JoinURL(c.webURL, nil, project, "-", "blob", c.ref(project, ref), path)
+}
+
+// SearchHTMLURL returns the web page of a project search. GitLab cannot scope it
+// to a user, so the query is searched across the instance.
+func (c *Client) SearchHTMLURL(username, query string) string {
+	return helper.JoinURL(c.webURL, url.Values{"search": {query}, "scope": {"projects"}}, "search")
+}
@@ -0,0 +1,138 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"ghexplorer/forge"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtures maps escaped API paths below /api/v4 to the bodies served by the test server.
+var fixtures = map[string]string{
+	"/users?username=gitlab-org":                                     `[]`,
+	"/groups/gitlab-org":                                             `{"id":9970,"name":"GitLab.org","full_path":"gitlab-org","description":"Open source software to collaborate on code"}`,
+	"/users?username=alice":                                          `[{"id":42,"username":"alice"}]`,
+	"/users/42":                                                      `{"id":42,"username":"alice","name":"Alice","bio":"Hacker","followers":5,"following":2}`,
+	"/groups/9970/projects":                                          `[{"name":"GitLab","path":"gitlab","path_with_namespace":"gitlab-org/gitlab","description":"The DevOps platform","default_branch":"master","star_count":4000,"forks_count":9000,"topics":["devops"],"visibility":"public","last_activity_at":"2024-06-01T08:00:00Z"},{"name":"Runner","path":"runner","path_with_namespace":"gitlab-org/ci/runner","default_branch":"main","visibility":"private","forked_from_project":{"id":1}}]`,
+	"/projects/gitlab-org%2Fgitlab/repository/tree":                  `[{"name":"app","type":"tree","path":"app","mode":"040000"},{"id":"a1b2c3","name":"README.md","type":"blob","path":"README.md","mode":"100644"},{"name":"link","type":"blob","path":"link","mode":"120000"},{"name":"vendor","type":"commit","path":"vendor","mode":"160000"}]`,
+	"/projects/gitlab-org%2Fgitlab/repository/files/link/raw":        "app",
+	"/projects/gitlab-org%2Fgitlab/repository/files/.gitmodules/raw": "[submodule \"vendor\"]\n\tpath = vendor\n\turl = ../vendor.git\n",
+	"/projects/gitlab-org%2Fci%2Frunner/repository/files/docs%2Findex.md/raw": "# Runner\n",
+	"/projects/gitlab-org%2Fgitlab/repository/branches":                       `[{"name":"master","commit":{"id":"b1c2d3"},"default":true}]`,
+	"/projects/gitlab-org%2Fgitlab/repository/tags":                           `[{"name":"v17.0.0-ee","commit":{"id":"e4f5a6"}}]`,
+	"/projects/gitlab-org%2Fgitlab/repository/files/README.md/raw":            "# GitLab\n",
+}
+
+// newTestClient starts a GitLab stand-in serving fixtures and returns a client pointed at it.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		key := r.URL.EscapedPath()[len("/api/v4"):]
+		if username := r.URL.Query().Get("username"); username != "" {
+			key += "?username=" + username
+		}
+		body, ok := fixtures[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "secret")
+	assert.NoError(t, err)
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/api/v4", client.baseURL.String())
+	assert.Equal(t, "https://gitlab.com/gitlab-org/gitlab/-/blob/HEAD/doc/index.md", client.FileHTMLURL("gitlab-org", "gitlab", "", "/doc/index.md"))
+	assert.Equal(t, "https://gitlab.com/gitlab-org/gitlab/-/blob/v17.0.0-ee/doc/index.md", client.FileHTMLURL("gitlab-org", "gitlab", "v17.0.0-ee", "/doc/index.md"))
+}
+
+func TestGetProfile(t *testing.T) {
+	client := newTestClient(t)
+
+	profile, err := client.GetProfile(context.Background(), "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", profile.Name)
+	assert.Equal(t, "Hacker", profile.Description)
+	assert.Equal(t, 5, profile.Followers)
+
+	profile, err = client.GetProfile(context.Background(), "gitlab-org")
+	assert.NoError(t, err)
+	assert.Equal(t, "GitLab.org", profile.Name)
+	assert.Equal(t, "gitlab-org", profile.Login)
+}
+
+func TestListRepositories(t *testing.T) {
+	client := newTestClient(t)
+	repos, err := client.ListRepositories(context.Background(), "gitlab-org")
+	assert.NoError(t, err)
+	assert.Len(t, repos, 2)
+	assert.Equal(t, "gitlab", repos[0].Slug())
+	assert.Equal(t, "ci/runner", repos[1].Slug())
+	assert.Equal(t, "Runner", repos[1].Name)
+	assert.Equal(t, 4000, repos[0].Stars)
+	assert.Equal(t, []string{"devops"}, repos[0].Topics)
+	assert.Empty(t, repos[0].Flags())
+	assert.Equal(t, []string{"fork", "private"}, repos[1].Flags())
+
+	content, err := client.GetFile(context.Background(), "gitlab-org", repos[1].Slug(), "", "/docs/index.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# Runner\n", string(content.Content))
+	assert.Contains(t, client.FileHTMLURL("gitlab-org", "gitlab", "", "README.md"), "/gitlab-org/gitlab/-/blob/master/README.md")
+}
+
+func TestRefs(t *testing.T) {
+	client := newTestClient(t)
+	branches, err := client.ListBranches(context.Background(), "gitlab-org", "gitlab")
+	assert.NoError(t, err)
+	assert.Equal(t, []*forge.Ref{{Name: "master", SHA: "b1c2d3"}}, branches)
+
+	tags, err := client.ListTags(context.Background(), "gitlab-org", "gitlab")
+	assert.NoError(t, err)
+	assert.Equal(t, []*forge.Ref{{Name: "v17.0.0-ee", SHA: "e4f5a6"}}, tags)
+}
+
+func TestGetTree(t *testing.T) {
+	tree, err := newTestClient(t).GetTree(context.Background(), "gitlab-org", "gitlab", "")
+	assert.NoError(t, err)
+	assert.Len(t, tree.Entries, 4)
+	assert.Equal(t, "submodule", tree.Entries[3].Type)
+	assert.Contains(t, tree.Entries[1].HTMLURL, "/gitlab-org/gitlab/-/blob/HEAD/README.md")
+}
+
+func TestGetReadme(t *testing.T) {
+	readme, err := newTestClient(t).GetReadme(context.Background(), "gitlab-org", "gitlab", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "# GitLab\n", string(readme.Content))
+}
+
+func TestListContents(t *testing.T) {
+	contents, err := newTestClient(t).ListContents(context.Background(), "gitlab-org", "gitlab", "", "")
+	assert.NoError(t, err)
+	assert.Len(t, contents, 4)
+
+	types := make(map[string]string)
+	for _, entry := range contents {
+		types[entry.Name] = entry.Type
+	}
+	assert.Equal(t, map[string]string{"app": "dir", "README.md": "file", "link": "symlink", "vendor": "submodule"}, types)
+	assert.Equal(t, "a1b2c3", contents[1].SHA)
+	assert.True(t, contents[1].SizeUnknown)
+	assert.Contains(t, contents[1].DownloadURL, "/gitlab-org/gitlab/-/raw/HEAD/README.md")
+	assert.Equal(t, "app", contents[2].Target)
+	assert.Equal(t, "../vendor.git", contents[3].SubmoduleGitURL)
+
+	_, err = newTestClient(t).ListContents(context.Background(), "gitlab-org", "missing", "", "")
+	assert.ErrorIs(t, err, forge.ErrNotFound)
+}
@@ -1,5 +1,11 @@
 package helper
 
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
 // StringOrNA handle potentially nil strings
 func StringOrNA(s string) string {
 	if s == "" {
@@ -7,3 +13,38 @@ func StringOrNA(s string) string {
 	}
 	return s
 }
+
+// JoinURL appends escaped path segments and a query to base.
+// Segments may themselves contain slashes, as repository paths do.
+func JoinURL(base *url.URL, query url.Values, segments ...string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(base.String(), "/"))
+	for _, segment := range segments {
+		for _, part := range strings.Split(segment, "/") {
+			if part == "" {
+				continue
+			}
+			b.WriteString("/")
+			b.WriteString(url.PathEscape(part))
+		}
+	}
+	if len(query) > 0 {
+		b.WriteString("?")
+		b.WriteString(query.Encode())
+	}
+	return b.String()
+}
+
+// FormatSize renders a byte count with a binary unit, such as "1.5 KB"
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
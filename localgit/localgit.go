@@ -0,0 +1,491 @@
+package localgit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"ghexplorer/forge"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorkingTree is the repository name under which the checked out files are browsed.
+// Git forbids spaces in ref names, so it cannot clash with a branch or tag.
+const WorkingTree = "working tree"
+
+// Repo is a forge.Provider reading a git repository on disk through the git
+// command. Its single profile is the repository itself; the working tree, branches
+// and tags are listed as its repositories.
+type Repo struct {
+	root string
+}
+
+var _ forge.Provider = (*Repo)(nil)
+
+// Commit is an entry of the commit log
+type Commit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+}
+
+// Open returns the repository containing dir
+func Open(dir string) (*Repo, error) {
+	out, err := git(context.Background(), dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+	return &Repo{root: strings.TrimSpace(string(out))}, nil
+}
+
+// Root returns the top-level directory of the working tree
+func (r *Repo) Root() string {
+	return r.root
+}
+
+// Name returns the name of the repository directory, used as its profile login
+func (r *Repo) Name() string {
+	return filepath.Base(r.root)
+}
+
+// git runs a git command in dir and returns its standard output. The command
+// is killed when ctx is done.
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// git runs a git command in the repository
+func (r *Repo) git(ctx context.Context, args ...string) ([]byte, error) {
+	return git(ctx, r.root, args...)
+}
+
+// GetProfile describes the repository and its checked out commit
+func (r *Repo) GetProfile(ctx context.Context, username string) (*forge.Profile, error) {
+	description := r.root
+	if log, err := r.Log(ctx, "HEAD", 1); err == nil && len(log) > 0 {
+		description = fmt.Sprintf("%s • HEAD %s %s", r.root, log[0].Hash, log[0].Subject)
+	}
+	return &forge.Profile{
+		Name:        r.Name(),
+		Login:       r.Name(),
+		Description: description,
+	}, nil
+}
+
+// ListRepositories lists the working tree, the branches and the tags, described by their last commit
+func (r *Repo) ListRepositories(ctx context.Context, username string) ([]*forge.Repository, error) {
+	out, err := r.git(ctx, "for-each-ref", "--format=%(refname:short)%00%(objectname:short) %(contents:subject)%00%(committerdate:iso-strict)", "refs/heads", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	repos := []*forge.Repository{{Name: WorkingTree, Description: "Files checked out in " + r.root}}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		repo := &forge.Repository{Name: fields[0], Description: fields[1]}
+		// Annotated tags pointing at trees or blobs have no committer date
+		if date, err := time.Parse(time.RFC3339, fields[2]); err == nil {
+			repo.UpdatedAt = date
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// SearchRepositories matches query against the ref names, also accepting any
+// revision git can resolve, such as a commit SHA
+func (r *Repo) SearchRepositories(ctx context.Context, username, query string) ([]*forge.Repository, error) {
+	repos, err := r.ListRepositories(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*forge.Repository
+	for _, repo := range repos {
+		if strings.Contains(strings.ToLower(repo.Name), strings.ToLower(query)) {
+			matches = append(matches, repo)
+		}
+	}
+	if len(matches) == 0 {
+		if log, err := r.Log(ctx, query, 1); err == nil && len(log) > 0 {
+			matches = append(matches, &forge.Repository{Name: query, Description: log[0].Hash + " " + log[0].Subject})
+		}
+	}
+	return matches, nil
+}
+
+// Log returns the last n commits reachable from ref
+func (r *Repo) Log(ctx context.Context, ref string, n int) ([]Commit, error) {
+	if err := checkRef(ref); err != nil {
+		return nil, err
+	}
+	out, err := r.git(ctx, "log", fmt.Sprintf("-n%d", n), "--format=%h%x00%an%x00%ad%x00%s", "--date=short", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, Commit{Hash: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]})
+	}
+	return commits, nil
+}
+
+// cleanPath normalizes a repository path so that it cannot leave the repository
+func cleanPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+// localPath resolves a repository path inside the working tree
+func (r *Repo) localPath(p string) string {
+	return filepath.Join(r.root, filepath.FromSlash(cleanPath(p)))
+}
+
+// treeish builds the git object name of a path at ref
+func treeish(ref, p string) (string, error) {
+	if err := checkRef(ref); err != nil {
+		return "", err
+	}
+	return ref + ":" + cleanPath(p), nil
+}
+
+// checkRef rejects revisions git would parse as options
+func checkRef(ref string) error {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid revision %q", ref)
+	}
+	return nil
+}
+
+// ListBranches lists the local branches
+func (r *Repo) ListBranches(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	return r.listRefs(ctx, "refs/heads")
+}
+
+// ListTags lists the tags
+func (r *Repo) ListTags(ctx context.Context, owner, repo string) ([]*forge.Ref, error) {
+	return r.listRefs(ctx, "refs/tags")
+}
+
+// listRefs lists the refs below prefix with the commits they point to
+func (r *Repo) listRefs(ctx context.Context, prefix string) ([]*forge.Ref, error) {
+	// Annotated tags point to a tag object, peeled to its commit by %(*objectname)
+	out, err := r.git(ctx, "for-each-ref", "--format=%(refname:short)%00%(objectname)%00%(*objectname)", prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*forge.Ref
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		ref := &forge.Ref{Name: fields[0], SHA: fields[1]}
+		if fields[2] != "" {
+			ref.SHA = fields[2]
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// revision returns the revision files are read at: ref when set, else the one
+// the repository is named after
+func revision(repo, ref string) string {
+	if ref != "" {
+		return ref
+	}
+	return repo
+}
+
+// ListContents lists a directory of the working tree or of a ref
+func (r *Repo) ListContents(ctx context.Context, owner, repo, ref, path string) ([]*forge.FileInfo, error) {
+	repo = revision(repo, ref)
+	if repo == WorkingTree {
+		return r.listWorkingTree(path)
+	}
+
+	object, err := treeish(repo, path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.git(ctx, "ls-tree", "-l", "-z", object)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []*forge.FileInfo
+	var gitmodules map[string]string
+	for _, entry := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		// <mode> SP <type> SP <object> SP+ <size> TAB <name>
+		meta, name, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 {
+			continue
+		}
+		info := &forge.FileInfo{
+			Name: name,
+			Type: forge.EntryType(fields[0], fields[1]),
+			Path: cleanPath(path + "/" + name),
+			SHA:  fields[2],
+		}
+		info.Size, _ = strconv.ParseInt(fields[3], 10, 64)
+
+		switch info.Type {
+		case "symlink":
+			if target, err := r.git(ctx, "cat-file", "blob", info.SHA); err == nil {
+				info.Target = string(target)
+			}
+		case "submodule":
+			if gitmodules == nil {
+				gitmodules = make(map[string]string)
+				if content, err := r.git(ctx, "cat-file", "blob", repo+":.gitmodules"); err == nil {
+					gitmodules = forge.ParseGitmodules(string(content))
+				}
+			}
+			info.SubmoduleGitURL = gitmodules[info.Path]
+		}
+		contents = append(contents, info)
+	}
+	return contents, nil
+}
+
+// GetTree lists every file and directory of the working tree or of a ref
+func (r *Repo) GetTree(ctx context.Context, owner, repo, ref string) (*forge.Tree, error) {
+	repo = revision(repo, ref)
+	if repo == WorkingTree {
+		return r.walkWorkingTree()
+	}
+	if err := checkRef(repo); err != nil {
+		return nil, err
+	}
+	out, err := r.git(ctx, "ls-tree", "-r", "-t", "-l", "-z", repo)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &forge.Tree{}
+	for _, entry := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		meta, name, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 {
+			continue
+		}
+		info := &forge.FileInfo{
+			Name: path.Base(name),
+			Type: forge.EntryType(fields[0], fields[1]),
+			Path: name,
+			SHA:  fields[2],
+		}
+		// Trees and submodules have no size
+		info.Size, _ = strconv.ParseInt(fields[3], 10, 64)
+		if info.Type == "symlink" {
+			if target, err := r.git(ctx, "cat-file", "blob", info.SHA); err == nil {
+				info.Target = string(target)
+			}
+		}
+		tree.Entries = append(tree.Entries, info)
+	}
+	return tree, nil
+}
+
+// walkWorkingTree lists the checked out files, hiding the .git directory
+func (r *Repo) walkWorkingTree() (*forge.Tree, error) {
+	tree := &forge.Tree{}
+	err := filepath.WalkDir(r.root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == r.root {
+			return nil
+		}
+		if entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(r.root, p)
+		if err != nil {
+			return err
+		}
+		info := &forge.FileInfo{Name: entry.Name(), Type: "file", Path: filepath.ToSlash(rel)}
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			info.Type = "symlink"
+			info.Target, _ = os.Readlink(p)
+		case entry.IsDir():
+			info.Type = "dir"
+		default:
+			if fileInfo, err := entry.Info(); err == nil {
+				info.Size = fileInfo.Size()
+			}
+		}
+		tree.Entries = append(tree.Entries, info)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// listWorkingTree lists a directory of the checked out files, hiding the .git directory
+func (r *Repo) listWorkingTree(path string) ([]*forge.FileInfo, error) {
+	entries, err := os.ReadDir(r.localPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []*forge.FileInfo
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+		info := &forge.FileInfo{Name: entry.Name(), Type: "file", Path: cleanPath(path + "/" + entry.Name())}
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			info.Type = "symlink"
+			info.Target, _ = os.Readlink(r.localPath(info.Path))
+		case entry.IsDir():
+			info.Type = "dir"
+		default:
+			if fileInfo, err := entry.Info(); err == nil {
+				info.Size = fileInfo.Size()
+			}
+			info.HTMLURL = r.FileHTMLURL(r.Name(), WorkingTree, "", info.Path)
+		}
+		contents = append(contents, info)
+	}
+	return contents, nil
+}
+
+// GetReadme reads the README at the root of the working tree or of a ref
+func (r *Repo) GetReadme(ctx context.Context, owner, repo, ref string) (*forge.File, error) {
+	return forge.ReadmeOf(ctx, r, owner, repo, ref)
+}
+
+// GetFile reads a file of the working tree or a blob of a ref
+func (r *Repo) GetFile(ctx context.Context, owner, repo, ref, path string) (*forge.File, error) {
+	repo = revision(repo, ref)
+	file := &forge.File{Path: cleanPath(path)}
+	if repo == WorkingTree {
+		f, err := os.Open(r.localPath(path))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if info, err := f.Stat(); err == nil {
+			file.Size = info.Size()
+		}
+		err = file.ReadContent(f)
+		if err != nil {
+			return nil, err
+		}
+		return file, nil
+	}
+
+	object, err := treeish(repo, path)
+	if err != nil {
+		return nil, err
+	}
+	sha, err := r.git(ctx, "rev-parse", "--verify", object)
+	if err != nil {
+		return nil, err
+	}
+	file.SHA = strings.TrimSpace(string(sha))
+	size, err := r.git(ctx, "cat-file", "-s", file.SHA)
+	if err != nil {
+		return nil, err
+	}
+	file.Size, _ = strconv.ParseInt(strings.TrimSpace(string(size)), 10, 64)
+	err = r.readBlob(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// readBlob streams the content of the blob file.SHA into file, stopping git
+// once forge.MaxFileSize bytes are read
+func (r *Repo) readBlob(ctx context.Context, file *forge.File) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", r.root, "cat-file", "blob", file.SHA)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	readErr := file.ReadContent(out)
+	if readErr != nil || file.Truncated {
+		// git waits for the rest of the blob to be read
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return readErr
+	}
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// FileHTMLURL returns a file URL for working tree files. Files of other refs
+// have no URL.
+func (r *Repo) FileHTMLURL(owner, repo, ref, path string) string {
+	if revision(repo, ref) != WorkingTree {
+		return ""
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(r.localPath(path))}).String()
+}
+
+// SearchHTMLURL returns an empty string as a local repository has no web interface
+func (r *Repo) SearchHTMLURL(username, query string) string {
+	return ""
+}
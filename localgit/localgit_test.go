@@ -0,0 +1,223 @@
+package localgit
+
+import (
+	"context"
+	"ghexplorer/forge"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRepo creates a repository with one commit on the main branch and an
+// uncommitted change in the working tree.
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		_, err := git(context.Background(), dir, args...)
+		if err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	run("config", "user.name", "Test")
+	run("config", "user.email", "test@example.com")
+	write("README.md", "# demo\n")
+	write("docs/guide.md", "guide\n")
+	if err := os.Symlink("docs/guide.md", filepath.Join(dir, "GUIDE")); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "Initial commit")
+	write("README.md", "# demo, edited\n")
+
+	repo, err := Open(filepath.Join(dir, "docs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestListRepositories(t *testing.T) {
+	repo := newTestRepo(t)
+
+	repos, err := repo.ListRepositories(context.Background(), repo.Name())
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 2) {
+		assert.Equal(t, WorkingTree, repos[0].Name)
+		assert.Equal(t, "main", repos[1].Name)
+		assert.Contains(t, repos[1].Description, "Initial commit")
+		assert.False(t, repos[1].UpdatedAt.IsZero())
+	}
+}
+
+func TestListContents(t *testing.T) {
+	repo := newTestRepo(t)
+
+	for _, ref := range []string{WorkingTree, "main"} {
+		contents, err := repo.ListContents(context.Background(), repo.Name(), ref, "", "")
+		assert.NoError(t, err)
+		types := map[string]string{}
+		for _, c := range contents {
+			types[c.Name] = c.Type
+		}
+		assert.Equal(t, map[string]string{"GUIDE": "symlink", "README.md": "file", "docs": "dir"}, types, ref)
+		for _, c := range contents {
+			switch c.Name {
+			case "GUIDE":
+				assert.Equal(t, "docs/guide.md", c.Target, ref)
+			case "README.md":
+				assert.NotZero(t, c.Size, ref)
+			}
+		}
+
+		contents, err = repo.ListContents(context.Background(), repo.Name(), ref, "", "docs")
+		assert.NoError(t, err)
+		if assert.Len(t, contents, 1, ref) {
+			assert.Equal(t, "guide.md", contents[0].Name)
+			assert.Equal(t, "docs/guide.md", contents[0].Path)
+		}
+	}
+}
+
+func TestGetFile(t *testing.T) {
+	repo := newTestRepo(t)
+
+	file, err := repo.GetFile(context.Background(), repo.Name(), WorkingTree, "", "README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# demo, edited\n", string(file.Content))
+	assert.Equal(t, int64(15), file.Size)
+
+	file, err = repo.GetFile(context.Background(), repo.Name(), "main", "", "README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# demo\n", string(file.Content))
+	assert.Len(t, file.SHA, 40)
+
+	file, err = repo.GetFile(context.Background(), repo.Name(), WorkingTree, "", "../../etc/passwd")
+	assert.Error(t, err)
+	assert.Nil(t, file)
+}
+
+func TestGetLargeBlob(t *testing.T) {
+	repo := newTestRepo(t)
+	content := strings.Repeat("a", forge.MaxFileSize+100)
+	if err := os.WriteFile(filepath.Join(repo.Root(), "large.txt"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "large.txt"}, {"commit", "-q", "-m", "Add a large file"}} {
+		if _, err := repo.git(context.Background(), args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := repo.GetFile(context.Background(), repo.Name(), "main", "", "large.txt")
+	assert.NoError(t, err)
+	assert.True(t, file.Truncated)
+	assert.Len(t, file.Content, forge.MaxFileSize)
+	assert.Equal(t, int64(len(content)), file.Size)
+}
+
+func TestGetReadme(t *testing.T) {
+	repo := newTestRepo(t)
+
+	readme, err := repo.GetReadme(context.Background(), repo.Name(), "main", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "# demo\n", string(readme.Content))
+
+	readme, err = repo.GetReadme(context.Background(), repo.Name(), WorkingTree, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "# demo, edited\n", string(readme.Content))
+}
+
+func TestRefs(t *testing.T) {
+	repo := newTestRepo(t)
+	_, err := repo.git(context.Background(), "tag", "-a", "v1.0", "-m", "First release")
+	assert.NoError(t, err)
+	head, err := repo.git(context.Background(), "rev-parse", "HEAD")
+	assert.NoError(t, err)
+
+	branches, err := repo.ListBranches(context.Background(), repo.Name(), WorkingTree)
+	assert.NoError(t, err)
+	if assert.Len(t, branches, 1) {
+		assert.Equal(t, "main", branches[0].Name)
+	}
+
+	// Annotated tags are peeled to their commit
+	tags, err := repo.ListTags(context.Background(), repo.Name(), WorkingTree)
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "v1.0", tags[0].Name)
+		assert.Equal(t, strings.TrimSpace(string(head)), tags[0].SHA)
+	}
+
+	// The ref overrides the revision the repository is named after
+	file, err := repo.GetFile(context.Background(), repo.Name(), WorkingTree, "v1.0", "README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# demo\n", string(file.Content))
+}
+
+func TestLogAndSearch(t *testing.T) {
+	repo := newTestRepo(t)
+
+	log, err := repo.Log(context.Background(), "main", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, log, 1) {
+		assert.Equal(t, "Test", log[0].Author)
+		assert.Equal(t, "Initial commit", log[0].Subject)
+
+		repos, err := repo.SearchRepositories(context.Background(), repo.Name(), log[0].Hash)
+		assert.NoError(t, err)
+		if assert.Len(t, repos, 1) {
+			assert.Equal(t, log[0].Hash, repos[0].Name)
+		}
+	}
+
+	repos, err := repo.SearchRepositories(context.Background(), repo.Name(), "mai")
+	assert.NoError(t, err)
+	assert.Len(t, repos, 1)
+
+	_, err = repo.Log(context.Background(), "-x", 1)
+	assert.Error(t, err)
+	_, err = repo.ListContents(context.Background(), repo.Name(), "--output=x", "", "")
+	assert.Error(t, err)
+}
+
+func TestGetTree(t *testing.T) {
+	repo := newTestRepo(t)
+
+	for _, ref := range []string{WorkingTree, "main"} {
+		tree, err := repo.GetTree(context.Background(), repo.Name(), ref, "")
+		assert.NoError(t, err)
+		types := map[string]string{}
+		for _, entry := range tree.Entries {
+			types[entry.Path] = entry.Type
+			if entry.Type == "symlink" {
+				assert.Equal(t, "docs/guide.md", entry.Target, ref)
+			}
+		}
+		assert.Equal(t, map[string]string{"GUIDE": "symlink", "README.md": "file", "docs": "dir", "docs/guide.md": "file"}, types, ref)
+	}
+
+	_, err := repo.GetTree(context.Background(), repo.Name(), "missing", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,311 @@
+// Package markdown renders Markdown documents for the terminal: headings,
+// lists, tables and highlighted code blocks, with links listed as footnotes.
+package markdown
+
+import (
+	"fmt"
+	"ghexplorer/config"
+	"ghexplorer/syntax"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// minWidth is the narrowest width documents are wrapped to
+const minWidth = 20
+
+// parser parses GitHub Flavored Markdown
+var parser = goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+
+// IsMarkdown reports whether the file named name is a Markdown document, from its extension
+func IsMarkdown(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	default:
+		return false
+	}
+}
+
+// Render renders the Markdown source wrapped to width columns. The links are
+// numbered and listed below the document.
+func Render(source []byte, width int) string {
+	r := &renderer{source: source, numbers: make(map[string]int)}
+	lines := r.blocks(parser.Parse(text.NewReader(source)), max(width, minWidth))
+	if len(r.links) > 0 {
+		lines = append(lines, "", config.MarkdownRuleStyle.Render(strings.Repeat("─", max(width, minWidth))))
+		for i, link := range r.links {
+			lines = append(lines, config.MarkdownRuleStyle.Render(fmt.Sprintf("[%d] ", i+1))+link)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderer renders a document, collecting the footnotes of its links
+type renderer struct {
+	source  []byte
+	links   []string
+	numbers map[string]int
+}
+
+// blocks renders the blocks below parent, separated by blank lines
+func (r *renderer) blocks(parent ast.Node, width int) []string {
+	var lines []string
+	for node := parent.FirstChild(); node != nil; node = node.NextSibling() {
+		if len(lines) > 0 && !isTight(node) {
+			lines = append(lines, "")
+		}
+		lines = append(lines, r.block(node, width)...)
+	}
+	return lines
+}
+
+// isTight reports whether node follows the previous block without a blank
+// line, as the nested lists of tight list items do
+func isTight(node ast.Node) bool {
+	_, ok := node.PreviousSibling().(*ast.TextBlock)
+	return ok
+}
+
+// block renders a block wrapped to width
+func (r *renderer) block(node ast.Node, width int) []string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		lines := wrap(r.inline(node), width)
+		for i, line := range lines {
+			lines[i] = config.MarkdownHeadingStyle.Render(line)
+		}
+		switch node.Level {
+		case 1:
+			return append(lines, config.MarkdownHeadingStyle.Render(strings.Repeat("═", width)))
+		case 2:
+			return append(lines, config.MarkdownHeadingStyle.Render(strings.Repeat("─", width)))
+		default:
+			lines[0] = config.MarkdownHeadingStyle.Render(strings.Repeat("#", node.Level)+" ") + lines[0]
+			return lines
+		}
+	case *ast.Paragraph, *ast.TextBlock:
+		return wrap(r.inline(node), width)
+	case *ast.ThematicBreak:
+		return []string{config.MarkdownRuleStyle.Render(strings.Repeat("─", width))}
+	case *ast.Blockquote:
+		lines := r.blocks(node, width-2)
+		for i, line := range lines {
+			lines[i] = config.MarkdownQuoteStyle.Render("│ ") + line
+		}
+		return lines
+	case *ast.List:
+		return r.list(node, width)
+	case *ast.FencedCodeBlock:
+		return r.code(node, string(node.Language(r.source)), width)
+	case *ast.CodeBlock:
+		return r.code(node, "", width)
+	case *ast.HTMLBlock:
+		var lines []string
+		for i := 0; i < node.Lines().Len(); i++ {
+			line := node.Lines().At(i)
+			lines = append(lines, config.MarkdownRuleStyle.Render(strings.TrimRight(string(line.Value(r.source)), "\n")))
+		}
+		return lines
+	case *east.Table:
+		return r.table(node, width)
+	default:
+		return r.blocks(node, width)
+	}
+}
+
+// list renders the items of a list after their bullet or number
+func (r *renderer) list(node *ast.List, width int) []string {
+	var lines []string
+	number := node.Start
+	for item := node.FirstChild(); item != nil; item = item.NextSibling() {
+		if len(lines) > 0 && !node.IsTight {
+			lines = append(lines, "")
+		}
+		marker := "• "
+		if node.IsOrdered() {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+		indent := strings.Repeat(" ", ansi.StringWidth(marker))
+		for i, line := range r.blocks(item, width-len(indent)) {
+			if i == 0 {
+				lines = append(lines, marker+line)
+			} else if line == "" {
+				lines = append(lines, line)
+			} else {
+				lines = append(lines, indent+line)
+			}
+		}
+	}
+	return lines
+}
+
+// code renders a code block indented, highlighted when its language is known.
+// Lines longer than width are broken where they reach it.
+func (r *renderer) code(node ast.Node, language string, width int) []string {
+	var code strings.Builder
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		code.Write(line.Value(r.source))
+	}
+	content := strings.TrimSuffix(code.String(), "\n")
+	content = strings.ReplaceAll(content, "\t", strings.Repeat(" ", config.TabWidth))
+
+	lines, ok := syntax.HighlightCode(language, content, config.SyntaxTheme, lipgloss.ColorProfile())
+	if !ok {
+		lines = strings.Split(content, "\n")
+		for i, line := range lines {
+			lines[i] = config.MarkdownCodeStyle.Render(line)
+		}
+	}
+	var wrapped []string
+	for _, line := range lines {
+		for _, row := range strings.Split(ansi.Hardwrap(line, max(1, width-4), true), "\n") {
+			wrapped = append(wrapped, "    "+row)
+		}
+	}
+	return wrapped
+}
+
+// table renders a table with aligned columns, shrinking the columns of tables
+// wider than width
+func (r *renderer) table(node *east.Table, width int) []string {
+	var rows [][]string
+	for row := node.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, r.inline(cell))
+		}
+		rows = append(rows, cells)
+	}
+	columns := len(node.Alignments)
+	if columns == 0 {
+		return nil
+	}
+
+	widths := make([]int, columns)
+	for _, cells := range rows {
+		for i, cell := range cells[:min(len(cells), columns)] {
+			widths[i] = max(widths[i], ansi.StringWidth(cell))
+		}
+	}
+	// Columns are separated by " │ "
+	available := width - 3*(columns-1)
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	if total > available {
+		for i := range widths {
+			widths[i] = max(1, min(widths[i], available/columns))
+		}
+	}
+
+	var lines []string
+	for n, cells := range rows {
+		rendered := make([]string, columns)
+		for i := range rendered {
+			cell := ""
+			if i < len(cells) {
+				cell = ansi.Truncate(cells[i], widths[i], "…")
+			}
+			rendered[i] = align(cell, widths[i], node.Alignments[i])
+		}
+		lines = append(lines, strings.Join(rendered, " │ "))
+		if n == 0 {
+			rules := make([]string, columns)
+			for i, w := range widths {
+				rules[i] = strings.Repeat("─", w)
+			}
+			lines = append(lines, strings.Join(rules, "─┼─"))
+		}
+	}
+	return lines
+}
+
+// align pads cell to width columns, as aligned by alignment
+func align(cell string, width int, alignment east.Alignment) string {
+	padding := max(0, width-ansi.StringWidth(cell))
+	switch alignment {
+	case east.AlignRight:
+		return strings.Repeat(" ", padding) + cell
+	case east.AlignCenter:
+		return strings.Repeat(" ", padding/2) + cell + strings.Repeat(" ", padding-padding/2)
+	default:
+		return cell + strings.Repeat(" ", padding)
+	}
+}
+
+// inline renders the inline content of parent
+func (r *renderer) inline(parent ast.Node) string {
+	var b strings.Builder
+	for node := parent.FirstChild(); node != nil; node = node.NextSibling() {
+		switch node := node.(type) {
+		case *ast.Text:
+			b.Write(node.Segment.Value(r.source))
+			switch {
+			case node.HardLineBreak():
+				b.WriteString("\n")
+			case node.SoftLineBreak():
+				b.WriteString(" ")
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		case *ast.CodeSpan:
+			b.WriteString(config.MarkdownCodeStyle.Render(r.inline(node)))
+		case *ast.Emphasis:
+			style := lipgloss.NewStyle().Italic(true)
+			if node.Level > 1 {
+				style = lipgloss.NewStyle().Bold(true)
+			}
+			b.WriteString(style.Render(r.inline(node)))
+		case *east.Strikethrough:
+			b.WriteString(lipgloss.NewStyle().Strikethrough(true).Render(r.inline(node)))
+		case *ast.Link:
+			b.WriteString(config.MarkdownLinkStyle.Render(r.inline(node)) + r.footnote(string(node.Destination)))
+		case *ast.Image:
+			b.WriteString(config.MarkdownLinkStyle.Render("[image: "+r.inline(node)+"]") + r.footnote(string(node.Destination)))
+		case *ast.AutoLink:
+			b.WriteString(config.MarkdownLinkStyle.Render(string(node.Label(r.source))))
+		case *ast.RawHTML:
+			for i := 0; i < node.Segments.Len(); i++ {
+				segment := node.Segments.At(i)
+				b.Write(segment.Value(r.source))
+			}
+		case *east.TaskCheckBox:
+			if node.IsChecked {
+				b.WriteString("[x] ")
+			} else {
+				b.WriteString("[ ] ")
+			}
+		default:
+			b.WriteString(r.inline(node))
+		}
+	}
+	return b.String()
+}
+
+// footnote returns the marker of the footnote listing destination, numbering
+// each destination once
+func (r *renderer) footnote(destination string) string {
+	number, ok := r.numbers[destination]
+	if !ok {
+		r.links = append(r.links, destination)
+		number = len(r.links)
+		r.numbers[destination] = number
+	}
+	return config.MarkdownRuleStyle.Render(fmt.Sprintf("[%d]", number))
+}
+
+// wrap wraps text to width columns, breaking words longer than width
+func wrap(text string, width int) []string {
+	return strings.Split(ansi.Wrap(text, width, ""), "\n")
+}
@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMarkdown(t *testing.T) {
+	assert.True(t, IsMarkdown("README.md"))
+	assert.True(t, IsMarkdown("docs/Guide.MARKDOWN"))
+	assert.False(t, IsMarkdown("README"))
+	assert.False(t, IsMarkdown("main.go"))
+}
+
+func TestRender(t *testing.T) {
+	source := `# Title
+
+Read the [guide](https://example.com/guide) and the [API](https://example.com/api), or the [guide](https://example.com/guide) again.
+
+- one
+- two
+  - nested
+- [x] done
+
+1. first
+2. second
+
+> quoted
+
+` + "```" + `
+make build
+` + "```" + `
+
+| Name | Stars |
+|:-----|------:|
+| tea | 12 |
+| chroma | 4000 |
+`
+	rendered := ansi.Strip(Render([]byte(source), 40))
+	lines := strings.Split(rendered, "\n")
+	assert.Equal(t, "Title", lines[0])
+	assert.Equal(t, strings.Repeat("═", 40), lines[1])
+
+	// Text is wrapped to the width and links are numbered once
+	for _, line := range lines {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 40, line)
+	}
+	assert.Contains(t, rendered, "Read the guide[1] and the API[2], or the\nguide[1] again.")
+	assert.Contains(t, rendered, "[1] https://example.com/guide\n[2] https://example.com/api")
+
+	assert.Contains(t, rendered, "• two\n  • nested\n• [x] done")
+	assert.Contains(t, rendered, "1. first\n2. second")
+	assert.Contains(t, rendered, "│ quoted")
+	assert.Contains(t, rendered, "    make build")
+	assert.Contains(t, rendered, "Name   │ Stars\n───────┼──────\ntea    │    12\nchroma │  4000")
+}
+
+func TestRenderNarrowCode(t *testing.T) {
+	source := "- item\n\n  ```\n  " + strings.Repeat("x", 50) + "\n\tindented\n  ```\n"
+	rendered := ansi.Strip(Render([]byte(source), 20))
+	for _, line := range strings.Split(rendered, "\n") {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 20, line)
+	}
+	assert.Equal(t, 50, strings.Count(rendered, "x"))
+}
+
+func TestRenderNarrowTable(t *testing.T) {
+	rendered := ansi.Strip(Render([]byte("| Name | Description |\n|---|---|\n| tea | A command line tool for Gitea servers |\n"), 20))
+	for _, line := range strings.Split(rendered, "\n") {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 20, line)
+	}
+	assert.Contains(t, rendered, "…")
+}
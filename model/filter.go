@@ -0,0 +1,133 @@
+package model
+
+import (
+	"fmt"
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"ghexplorer/fuzzy"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// filterable reports whether the current view lists items the fuzzy filter narrows
+func (m Model) filterable() bool {
+	return m.currentView == "repositories" || m.currentView == "files"
+}
+
+// updateFilter edits the filter with the keys typed while filtering. It reports
+// false for the keys handled as usual, which move through and open the matches.
+func (m Model) updateFilter(msg tea.KeyMsg) (Model, bool) {
+	query := m.filters[m.currentView]
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.filtering = false
+		return m.setFilter(""), true
+	case tea.KeyEnter:
+		m.filtering = false
+		return m, false
+	case tea.KeyUp, tea.KeyDown:
+		return m, false
+	case tea.KeyBackspace:
+		runes := []rune(query)
+		if len(runes) == 0 {
+			return m, true
+		}
+		return m.setFilter(string(runes[:len(runes)-1])), true
+	case tea.KeySpace:
+		return m.setFilter(query + " "), true
+	case tea.KeyRunes:
+		return m.setFilter(query + string(msg.Runes)), true
+	default:
+		return m, true
+	}
+}
+
+// setFilter narrows the list of the current view to the matches of query, best first
+func (m Model) setFilter(query string) Model {
+	m.filters[m.currentView] = query
+	switch m.currentView {
+	case "repositories":
+		m = m.arrangeRepositories()
+	case "files":
+		m = m.filterFiles()
+	}
+	m.cursor = 0
+	return m
+}
+
+// filterFiles shows the listed directory entries matching the filter of the files view
+func (m Model) filterFiles() Model {
+	query := m.filters["files"]
+	if query == "" {
+		m.fileContents = m.listedFiles
+		return m
+	}
+
+	names := make([]string, len(m.listedFiles))
+	for i, entry := range m.listedFiles {
+		names[i] = entry.Name
+	}
+	m.fileContents = make([]*forge.FileInfo, 0, len(names))
+	for _, ranked := range fuzzy.Rank(query, names) {
+		m.fileContents = append(m.fileContents, m.listedFiles[ranked.Index])
+	}
+	return m
+}
+
+// rankRepositories orders repos by how well their name matches query, dropping the others
+func rankRepositories(repos []*forge.Repository, query string) []*forge.Repository {
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Name
+	}
+	matches := make([]*forge.Repository, 0, len(repos))
+	for _, ranked := range fuzzy.Rank(query, names) {
+		matches = append(matches, repos[ranked.Index])
+	}
+	return matches
+}
+
+// highlight renders text in style, emphasizing the runes matching the filter of view
+func (m Model) highlight(view, text string, style lipgloss.Style) string {
+	match, ok := fuzzy.Find(m.filters[view], text)
+	if !ok || len(match.Positions) == 0 {
+		return style.Render(text)
+	}
+
+	matched := make(map[int]bool, len(match.Positions))
+	for _, i := range match.Positions {
+		matched[i] = true
+	}
+	matchStyle := config.MatchStyle.Inherit(style)
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		// Render each run of matched or unmatched runes at once
+		j := i
+		for j < len(runes) && matched[j] == matched[i] {
+			j++
+		}
+		if matched[i] {
+			b.WriteString(matchStyle.Render(string(runes[i:j])))
+		} else {
+			b.WriteString(style.Render(string(runes[i:j])))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// filterView renders the filter prompt of view with the number of matches
+func (m Model) filterView(view string, matches, total int) string {
+	query := m.filters[view]
+	if query == "" && !m.filtering {
+		return ""
+	}
+	prompt := config.DetailStyle.Render("Filter: ") + config.ValueStyle.Render(query)
+	if m.filtering {
+		prompt += config.ValueStyle.Render("▏")
+	}
+	return prompt + config.DetailStyle.Render(fmt.Sprintf("  %d of %d", matches, total)) + "\n\n"
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"fmt"
+	"ghexplorer/config"
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fileMatch is a match of the search in the line of the open file numbered
+// line+1, from byte start to end of its text
+type fileMatch struct {
+	line, start, end int
+}
+
+// openFind opens the prompt for the text to find in the open file, the search
+// starting from the line at the top of the viewport
+func (m Model) openFind() Model {
+	m.finding = true
+	m.jumping = false
+	m.findFrom = m.topLine()
+	return m
+}
+
+// updateFind edits the text found in the open file, finding it as it is typed.
+// It reports false for the keys handled as usual, which scroll the file.
+func (m Model) updateFind(msg tea.KeyMsg) (Model, bool) {
+	query := []rune(m.findQuery)
+	switch msg.String() {
+	case "ctrl+r":
+		m.findRegexp = !m.findRegexp
+		return m.find(), true
+	case "ctrl+t":
+		m.findCase = !m.findCase
+		return m.find(), true
+	}
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.finding = false
+		return m.clearFind(), true
+	case tea.KeyEnter:
+		m.finding = false
+		return m, true
+	case tea.KeyBackspace:
+		if len(query) == 0 {
+			return m, true
+		}
+		query = query[:len(query)-1]
+	case tea.KeySpace:
+		query = append(query, ' ')
+	case tea.KeyRunes:
+		query = append(query, msg.Runes...)
+	default:
+		return m, false
+	}
+	m.findQuery = string(query)
+	return m.find(), true
+}
+
+// find searches the open file and shows the first match from the line the
+// search started from
+func (m Model) find() Model {
+	m = m.search()
+	m.matchIndex = sort.Search(len(m.matches), func(i int) bool {
+		return m.matches[i].line+1 >= m.findFrom
+	})
+	if m.matchIndex == len(m.matches) {
+		m.matchIndex = 0
+	}
+	return m.showMatch()
+}
+
+// clearFind forgets the search, removing its matches from the open file
+func (m Model) clearFind() Model {
+	m.findQuery = ""
+	m.findErr = nil
+	m.matches = nil
+	m.matchIndex = 0
+	return m.layoutContent()
+}
+
+// search lists the matches of findQuery in the text of the open file as shown,
+// a regular expression when findRegexp is set, ignoring case unless findCase
+// is set
+func (m Model) search() Model {
+	m.matches = nil
+	m.findErr = nil
+	if m.findQuery == "" {
+		return m
+	}
+	pattern := m.findQuery
+	if !m.findRegexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !m.findCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		m.findErr = err
+		return m
+	}
+
+	for i, text := range m.shownText() {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			// Empty matches, such as those of ^, cannot be shown
+			if loc[1] > loc[0] {
+				m.matches = append(m.matches, fileMatch{line: i, start: loc[0], end: loc[1]})
+			}
+		}
+	}
+	m.matchIndex = min(m.matchIndex, max(0, len(m.matches)-1))
+	return m
+}
+
+// nextMatch shows the match delta matches after the one shown, wrapping
+// around the file, before it when delta is negative
+func (m Model) nextMatch(delta int) Model {
+	if len(m.matches) == 0 {
+		return m
+	}
+	m.matchIndex = ((m.matchIndex+delta)%len(m.matches) + len(m.matches)) % len(m.matches)
+	return m.showMatch()
+}
+
+// showMatch lays the open file out with the current match marked, scrolling
+// to it when it is out of view
+func (m Model) showMatch() Model {
+	if len(m.matches) == 0 {
+		return m.layoutContent()
+	}
+	match := m.matches[m.matchIndex]
+	return m.reveal(match.line, match.start, match.end)
+}
+
+// matchRanges returns the ranges of the matches of the search in the line of
+// the open file numbered i+1
+func (m Model) matchRanges(i int) []textRange {
+	var ranges []textRange
+	j := sort.Search(len(m.matches), func(j int) bool { return m.matches[j].line >= i })
+	for ; j < len(m.matches) && m.matches[j].line == i; j++ {
+		style := config.SearchMatchStyle
+		if j == m.matchIndex {
+			style = config.CurrentMatchStyle
+		}
+		ranges = append(ranges, textRange{start: m.matches[j].start, end: m.matches[j].end, style: style})
+	}
+	return ranges
+}
+
+// findView shows the find prompt while it is open, and how many matches the
+// search found
+func (m Model) findView() string {
+	var b strings.Builder
+	if m.finding {
+		b.WriteString(config.DetailStyle.Render(" • Find: ") + config.ValueStyle.Render(m.findQuery+"▏"))
+		var options []string
+		if m.findRegexp {
+			options = append(options, "regex")
+		}
+		if m.findCase {
+			options = append(options, "case-sensitive")
+		}
+		if len(options) > 0 {
+			b.WriteString(config.DetailStyle.Render(" (" + strings.Join(options, ", ") + ")"))
+		}
+	}
+	switch {
+	case m.findErr != nil:
+		b.WriteString(config.ErrorStyle.Render(" • " + m.findErr.Error()))
+	case m.findQuery == "":
+	case len(m.matches) == 0:
+		b.WriteString(config.DetailStyle.Render(" • no matches"))
+	default:
+		b.WriteString(config.DetailStyle.Render(fmt.Sprintf(" • match %d of %d", m.matchIndex+1, len(m.matches))))
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"fmt"
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"ghexplorer/fuzzy"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openFinder shows the go-to-file finder over every file of the repository
+// browsed, loading its tree
+func (m Model) openFinder() (tea.Model, tea.Cmd) {
+	m.currentView = "finder"
+	m.filters["finder"] = ""
+	m.cursor = 0
+	next, cmd := m.loadTree()
+	return next.(Model).rankFinder(), cmd
+}
+
+// updateFinder edits the path typed in the finder and opens the file chosen.
+// It reports false for the keys handled as usual, which move through the
+// matches and go back.
+func (m Model) updateFinder(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	query := []rune(m.filters["finder"])
+	switch msg.Type {
+	case tea.KeyEnter:
+		if m.cursor >= len(m.finderMatches) {
+			return m, nil, true
+		}
+		cmd := m.request(m.followLink(m.treeAt, "/"+m.finderMatches[m.cursor].Path))
+		return m, cmd, true
+	case tea.KeyBackspace:
+		if len(query) > 0 {
+			query = query[:len(query)-1]
+		}
+	case tea.KeySpace:
+		query = append(query, ' ')
+	case tea.KeyRunes:
+		query = append(query, msg.Runes...)
+	default:
+		return m, nil, false
+	}
+	m.filters["finder"] = string(query)
+	m.cursor = 0
+	return m.rankFinder(), nil, true
+}
+
+// rankFinder lists the files of the tree whose path matches the typed path,
+// best first
+func (m Model) rankFinder() Model {
+	m.finderMatches = nil
+	if m.tree == nil {
+		return m
+	}
+	var files []*forge.FileInfo
+	var paths []string
+	for _, entry := range m.tree.Entries {
+		if entry.Type == "file" || entry.Type == "symlink" {
+			files = append(files, entry)
+			paths = append(paths, entry.Path)
+		}
+	}
+	m.finderMatches = make([]*forge.FileInfo, 0, len(files))
+	for _, ranked := range fuzzy.Rank(m.filters["finder"], paths) {
+		m.finderMatches = append(m.finderMatches, files[ranked.Index])
+	}
+	return m
+}
+
+// finderView handles the CLI go-to-file finder view
+func (m Model) finderView() string {
+	var content strings.Builder
+
+	content.WriteString(m.bannerView())
+	content.WriteString(config.HeaderStyle.Render(fmt.Sprintf("Go to file in %s", m.selected["repository"])))
+	content.WriteString("\n\n")
+	content.WriteString(config.DetailStyle.Render("File: ") + config.ValueStyle.Render(m.filters["finder"]+"▏"))
+
+	if m.tree == nil {
+		content.WriteString("\n\n" + m.spinner.View() + " Loading files...")
+		return config.DocStyle.Render(content.String())
+	}
+	matches := m.finderMatches
+	content.WriteString(config.DetailStyle.Render(fmt.Sprintf("  %d matches", len(matches))))
+	content.WriteString("\n\n")
+	if m.tree.Truncated {
+		content.WriteString(config.StaleStyle.Render("The tree is too large to be listed entirely; some files are missing"))
+		content.WriteString("\n\n")
+	}
+
+	start := m.cursor / config.ItemsPerPage * config.ItemsPerPage
+	for i := start; i < min(start+config.ItemsPerPage, len(matches)); i++ {
+		line := m.highlight("finder", matches[i].Path, config.FileStyle)
+		if i == m.cursor {
+			content.WriteString("> " + config.SelectedStyle.Render(line) + "\n")
+		} else {
+			content.WriteString("  " + line + "\n")
+		}
+	}
+
+	content.WriteString(config.FooterStyle.Render("\nType part of a path • Enter to open the file • Esc to go back"))
+	return config.DocStyle.Render(content.String())
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"ghexplorer/markdown"
+)
+
+// renderMarkdown renders the open file to the width of the viewport when it is
+// a Markdown document
+func (m Model) renderMarkdown() Model {
+	m.markdown = ""
+	m.markdownLines = nil
+	if m.file == nil || m.file.Binary || !markdown.IsMarkdown(m.selected["file"]) {
+		return m
+	}
+	m.markdown = markdown.Render(m.file.Content, m.viewport.Width)
+	m.markdownLines = plainLines(m.markdown)
+	// The lines selected are those of the document as rendered before
+	if m.selectMode && !m.showSource {
+		m = m.stopSelection()
+	}
+	return m
+}
+
+// toggleSource switches the open Markdown document between its rendered and source views
+func (m Model) toggleSource() Model {
+	if m.markdown == "" {
+		return m
+	}
+	if m.selectMode {
+		m = m.stopSelection()
+	}
+	m.showSource = !m.showSource
+	m = m.search().layoutContent()
+	m.viewport.GotoTop()
+	return m
+}
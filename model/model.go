@@ -1,13 +1,17 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"ghexplorer/config"
-	"ghexplorer/github_api"
+	"ghexplorer/forge"
 	"ghexplorer/helper"
+	"path"
 	"strings"
+	"time"
+	"unicode/utf8"
 
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -17,29 +21,132 @@ import (
 
 // Model is the base model
 type Model struct {
+	provider     forge.Provider
 	githubID     string
 	inputting    bool
-	profile      *github_api.GitHubProfile
-	repositories []*github_api.Repository
+	profile      *forge.Profile
+	repositories []*forge.Repository
 	currentView  string
 	cursor       int
 	selected     map[string]string
-	fileContents []*github_api.FileInfo
+	fileContents []*forge.FileInfo
+	file         *forge.File
 	fileContent  string
 	searchQuery  string
-	errorMessage string
 	selectMode   bool
-	selectStart  int
-	selectEnd    int
 	textInput    textinput.Model
 	viewport     viewport.Model
 	spinner      spinner.Model
 	tabs         []string
 	activeTab    int
+	// parents are the superproject directories of the submodules entered
+	parents []location
+	banner  error
+	staleAt map[string]time.Time
+	// cancel aborts the fetch in flight
+	cancel context.CancelFunc
+	// listed are the repositories as fetched, shown filtered and sorted in repositories
+	listed     []*forge.Repository
+	repoSort   string
+	repoFilter forge.RepositoryFilter
+	// listedFiles is the directory listing fetched, shown filtered in fileContents
+	listedFiles []*forge.FileInfo
+	// filters are the fuzzy filters of the list views, edited while filtering
+	filters   map[string]string
+	filtering bool
+	// refs are the branches and tags offered by the ref picker, filtered by refQuery
+	refs     []refEntry
+	refQuery string
+	// tree is the whole tree of the repository at treeAt, grouped by directory
+	// in treeDirs and shown with the expanded directories open
+	tree     *forge.Tree
+	treeAt   location
+	treeDirs map[string][]*forge.FileInfo
+	expanded map[string]bool
+	// finderMatches are the files of the tree matching the path typed in the
+	// finder, best first
+	finderMatches []*forge.FileInfo
+	// highlighted are the lines of the open file highlighted, nil for plain
+	// text, kept in highlights by file. plainLines are the lines of the file
+	// without their colors, set along with highlighted.
+	highlighted []string
+	plainLines  []string
+	highlights  map[string][]string
+	// markdown is the open Markdown document rendered, shown unless showSource
+	// is set, and markdownLines its lines without their colors
+	markdown      string
+	markdownLines []string
+	showSource    bool
+	// lineStarts are the viewport rows where the lines of the open file start.
+	// Lines wrap unless noWrap is set, then they are scrolled sideways to
+	// column xOffset, at most maxXOffset.
+	lineStarts []int
+	noWrap     bool
+	xOffset    int
+	maxXOffset int
+	textWidth  int
+	// selectAnchor and selectCursor are the ends of the selection while in
+	// selectMode, selecting whole lines when selectLines is set. selectColumn
+	// is the column the cursor was last moved to.
+	selectAnchor textPosition
+	selectCursor textPosition
+	selectColumn int
+	selectLines  bool
+	// gotoLine is the line number typed in the go-to-line prompt, open while jumping
+	gotoLine string
+	jumping  bool
+	// findQuery is found in the open file as shown, edited while finding. It
+	// is a regular expression when findRegexp is set, and ignores case unless
+	// findCase is set. matches are listed in order, matchIndex being shown.
+	findQuery  string
+	findRegexp bool
+	findCase   bool
+	finding    bool
+	findFrom   int
+	findErr    error
+	matches    []fileMatch
+	matchIndex int
+	// profileReadme is shown in the readme viewport of the Overview tab, and
+	// repoReadme, rendered in repoReadmeText, below the root of a repository
+	profileReadme  *forge.File
+	readme         viewport.Model
+	repoReadme     *forge.File
+	repoReadmeText string
+}
+
+// profileMsg carries a fetched profile
+type profileMsg struct {
+	profile *forge.Profile
+	readme  *forge.File
+	staleAt time.Time
+}
+
+// repositoriesMsg carries a fetched or searched repository list
+type repositoriesMsg struct {
+	repositories []*forge.Repository
+	staleAt      time.Time
+}
+
+// contentsMsg carries a fetched directory listing
+type contentsMsg struct {
+	location
+	contents []*forge.FileInfo
+	readme   *forge.File
+	// readmeErr is why the README of the listing could not be fetched
+	readmeErr error
+	staleAt   time.Time
+	// parent is set when entering a submodule from the parent directory
+	parent *location
+}
+
+// fileContentMsg carries a fetched file
+type fileContentMsg struct {
+	file    *forge.File
+	staleAt time.Time
 }
 
 // InitialModel initialModel initialize the model
-func InitialModel(initialGithubID string) Model {
+func InitialModel(provider forge.Provider, initialGithubID string) Model {
 	ti := textinput.New()
 	ti.Placeholder = "Enter GitHub profile ID"
 	ti.Focus()
@@ -52,15 +159,20 @@ func InitialModel(initialGithubID string) Model {
 	vp.YPosition = config.HeaderHeight
 
 	m := Model{
+		provider:    provider,
 		githubID:    initialGithubID,
 		inputting:   initialGithubID == "",
 		currentView: "input",
 		selected:    make(map[string]string),
+		staleAt:     make(map[string]time.Time),
+		filters:     make(map[string]string),
+		highlights:  make(map[string][]string),
 		textInput:   ti,
 		spinner:     s,
 		tabs:        []string{"Overview", "Repositories"},
 		activeTab:   0,
 		viewport:    vp,
+		readme:      viewport.New(80, config.MinReadmeHeight),
 	}
 
 	// If initial GitHub ID is provided, set it in the text input
@@ -82,8 +194,47 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if msg.String() == "esc" && m.banner != nil {
+			m.banner = nil
+			return m, nil
+		}
+		if m.filtering {
+			var handled bool
+			if m, handled = m.updateFilter(msg); handled {
+				return m, nil
+			}
+		}
+		if m.jumping {
+			var handled bool
+			if m, handled = m.updateGotoLine(msg); handled {
+				return m, nil
+			}
+		}
+		if m.finding {
+			var handled bool
+			if m, handled = m.updateFind(msg); handled {
+				return m, nil
+			}
+		}
+		if m.selectMode && m.currentView == "fileContent" {
+			var handled bool
+			if m, handled = m.updateSelection(msg); handled {
+				return m, nil
+			}
+		}
+		if m.currentView == "refs" {
+			if model, cmd, handled := m.updateRefs(msg); handled {
+				return model, cmd
+			}
+		}
+		if m.currentView == "finder" {
+			if model, cmd, handled := m.updateFinder(msg); handled {
+				return model, cmd
+			}
+		}
 		switch msg.String() {
 		case "q":
+			m.cancelRequest()
 			return m, tea.Quit
 		case "tab":
 			if m.currentView == "profile" || m.currentView == "repositories" {
@@ -100,29 +251,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "input":
 				m.inputting = false
 				m.currentView = "profile"
-				return m, m.fetchProfile
+				cmd = m.request(m.fetchProfile)
+				return m, cmd
 			case "repositories":
 				if m.cursor < len(m.repositories) {
-					m.selected["repository"] = m.repositories[m.cursor].Name
+					m.selected["owner"] = m.profile.Login
+					m.selected["repository"] = m.repositories[m.cursor].Slug()
+					m.selected["ref"] = ""
 					m.currentView = "files"
 					m.cursor = 0
 					m.selected["path"] = ""
-					return m, m.fetchRepositoryContents
+					m.fileContents = nil
+					m.listedFiles = nil
+					m.parents = nil
+					cmd = m.request(m.fetchContents(m.location()))
+					return m, cmd
 				}
 			case "files":
 				if m.cursor < len(m.fileContents) {
-					if m.fileContents[m.cursor].Type == "file" {
-						m.selected["file"] = m.fileContents[m.cursor].Name
-						m.currentView = "fileContent"
-						return m, m.fetchFileContent
-					} else {
-						m.selected["path"] += "/" + m.fileContents[m.cursor].Name
-						return m, m.fetchRepositoryContents
-					}
+					return m.open(m.fileContents[m.cursor])
+				}
+			case "tree":
+				if rows := m.treeRows(); m.cursor < len(rows) {
+					return m.openTreeEntry(rows[m.cursor].entry)
 				}
 			case "search":
 				m.currentView = "repositories"
-				return m, m.searchRepositories
+				cmd = m.request(m.searchRepositories)
+				return m, cmd
 			}
 		case "backspace":
 			if m.inputting && len(m.githubID) > 0 {
@@ -131,48 +287,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
 			}
 		case "esc":
-			if m.selectMode {
-				m.selectMode = false
-				m.selectStart = 0
-				m.selectEnd = 0
+			if m.filterable() && m.filters[m.currentView] != "" {
+				m = m.setFilter("")
+			} else if m.currentView == "fileContent" && m.findQuery != "" {
+				m = m.clearFind()
 			} else {
-				switch m.currentView {
-				case "repositories":
-					m.currentView = "profile"
-				case "files":
-					if m.selected["path"] == "" {
-						m.currentView = "repositories"
-						m.cursor = 0
-					} else {
-						paths := strings.Split(m.selected["path"], "/")
-						m.selected["path"] = strings.Join(paths[:len(paths)-1], "/")
-						return m, m.fetchRepositoryContents
-					}
-				case "fileContent":
-					m.currentView = "files"
-					m.selectMode = false
-					m.selectStart = 0
-					m.selectEnd = 0
-				case "search":
-					m.currentView = "repositories"
-				}
+				return m.goBack()
 			}
 		case "up", "down", "pgup", "pgdown":
 			if m.currentView == "fileContent" {
-				if m.selectMode {
-					switch msg.String() {
-					case "up":
-						m.selectEnd = max(0, m.selectEnd-m.viewport.Width)
-					case "down":
-						m.selectEnd = min(len(m.fileContent), m.selectEnd+m.viewport.Width)
-					case "left":
-						m.selectEnd = max(0, m.selectEnd-1)
-					case "right":
-						m.selectEnd = min(len(m.fileContent), m.selectEnd+1)
-					}
-				}
 				m.viewport, cmd = m.viewport.Update(msg)
 				return m, cmd
+			} else if m.currentView == "profile" {
+				m.readme, cmd = m.readme.Update(msg)
+				return m, cmd
 			} else {
 				switch msg.String() {
 				case "up":
@@ -189,41 +317,77 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if m.cursor < len(m.fileContents)-1 {
 							m.cursor++
 						}
+					case "refs":
+						if m.cursor < len(m.refMatches())-1 {
+							m.cursor++
+						}
+					case "tree":
+						if m.cursor < len(m.treeRows())-1 {
+							m.cursor++
+						}
+					case "finder":
+						if m.cursor < len(m.finderMatches)-1 {
+							m.cursor++
+						}
 					}
 				}
 			}
-		case "ctrl+a":
+		case "left", "right":
 			if m.currentView == "fileContent" {
-				m.selectMode = true
-				m.selectStart = 0
-				m.selectEnd = len(m.fileContent)
-			}
-		case "ctrl+c":
-			if m.selectMode && m.currentView == "fileContent" {
-				selectedText := m.fileContent[m.selectStart:m.selectEnd]
-				clipboard.WriteAll(selectedText)
-				m.selectMode = false
-				m.selectStart = 0
-				m.selectEnd = 0
-				return m, nil
+				if msg.String() == "left" {
+					m = m.scrollSideways(-config.ScrollStep)
+				} else {
+					m = m.scrollSideways(config.ScrollStep)
+				}
 			}
-		case "ctrl+d":
-			if m.selectMode && m.currentView == "fileContent" {
-				m.selectMode = false
-				m.selectStart = 0
-				m.selectEnd = len(m.fileContent)
-				return m, nil
+		case "ctrl+a":
+			if m.currentView == "fileContent" {
+				m = m.selectAll()
 			}
 		case "/":
+			if m.filterable() {
+				m.filtering = true
+			} else if m.currentView == "fileContent" {
+				m = m.openFind()
+			}
+		case "ctrl+f":
 			if m.currentView == "repositories" {
 				m.currentView = "search"
 				m.searchQuery = ""
 			}
+		case "ctrl+p":
+			if m.currentView == "files" {
+				return m.openFinder()
+			}
+		case "ctrl+g":
+			if m.currentView == "fileContent" {
+				m = m.openGotoLine()
+			}
 		default:
 			if m.inputting {
 				m.githubID += msg.String()
 			} else if m.currentView == "search" {
 				m.searchQuery += msg.String()
+			} else if m.currentView == "repositories" {
+				m = m.arrange(msg.String())
+			} else if m.currentView == "files" && msg.String() == "r" {
+				return m.openRefs()
+			} else if m.currentView == "files" && msg.String() == "t" {
+				return m.openTree()
+			} else if m.currentView == "tree" && msg.String() == "t" {
+				return m.goBack()
+			} else if m.currentView == "fileContent" && msg.String() == "m" {
+				m = m.toggleSource()
+			} else if m.currentView == "fileContent" && msg.String() == "w" {
+				m = m.toggleWrap()
+			} else if m.currentView == "fileContent" && msg.String() == ":" {
+				m = m.openGotoLine()
+			} else if m.currentView == "fileContent" && (msg.String() == "v" || msg.String() == "V") {
+				m = m.startSelection(msg.String() == "V")
+			} else if m.currentView == "fileContent" && msg.String() == "n" {
+				m = m.nextMatch(1)
+			} else if m.currentView == "fileContent" && msg.String() == "N" {
+				m = m.nextMatch(-1)
 			}
 		}
 	case tea.WindowSizeMsg:
@@ -231,78 +395,205 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.viewport = viewport.New(width, height)
 		m.viewport.YPosition = config.HeaderHeight
 		m.viewport.HighPerformanceRendering = config.UseHighPerformanceRenderer
+		m = m.renderMarkdown()
+		m = m.resizeReadmes(width, msg.Height)
 		if m.currentView == "fileContent" {
-			m.viewport.SetContent(m.fileContent)
+			m = m.search().layoutContent()
 		}
-	case github_api.GitHubProfile:
-		m.profile = &msg
-		return m, m.fetchRepositories
-	case []*github_api.Repository:
-		m.repositories = msg
+	case profileMsg:
+		m.profile = msg.profile
+		m.profileReadme = msg.readme
+		m.readme.SetContent(renderReadme(m.profileReadme, m.readme.Width))
+		m.readme.GotoTop()
+		m.staleAt["profile"] = msg.staleAt
+		cmd = m.request(m.fetchRepositories)
+		return m, cmd
+	case repositoriesMsg:
+		m.listed = msg.repositories
+		m.staleAt["repositories"] = msg.staleAt
 		m.currentView = "repositories"
+		m = m.arrangeRepositories()
 		m.cursor = 0
-	case []*github_api.FileInfo:
-		m.fileContents = msg
-		m.cursor = 0
-	case string:
-		m.fileContent = msg
-		if m.currentView == "fileContent" {
-			m.viewport.SetContent(m.fileContent)
-			m.viewport.GotoTop()
+	case contentsMsg:
+		m = m.showContents(msg)
+	case fileContentMsg:
+		m = m.showFile(msg)
+	case refsMsg:
+		m.refs = msg.refs
+		if m.refs == nil {
+			m.refs = []refEntry{}
 		}
+	case treeMsg:
+		m = m.showTree(msg)
+	case linkedFileMsg:
+		m = m.showContents(msg.contents)
+		m.selected["file"] = msg.name
+		m.currentView = "fileContent"
+		m = m.showFile(msg.file)
 	case error:
-		m.currentView = "error"
-		m.errorMessage = msg.Error()
-		return m, nil
+		return m.failed(msg)
 	}
 	m.textInput, cmd = m.textInput.Update(msg)
 	return m, cmd
 }
 
+// goBack returns to the view the current one was opened from, abandoning the
+// fetch the current view is waiting for
+func (m Model) goBack() (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case "repositories":
+		m.cancelRequest()
+		m.currentView = "profile"
+	case "files":
+		switch {
+		case m.selected["path"] != "":
+			parent := m.location()
+			parent.path = path.Dir(parent.path)
+			if parent.path == "/" {
+				parent.path = ""
+			}
+			cmd := m.request(m.fetchContents(parent))
+			return m, cmd
+		case len(m.parents) > 0:
+			parent := m.parents[len(m.parents)-1]
+			m.parents = m.parents[:len(m.parents)-1]
+			cmd := m.request(m.fetchContents(parent))
+			return m, cmd
+		default:
+			m.cancelRequest()
+			m.currentView = "repositories"
+			m.cursor = 0
+		}
+	case "fileContent":
+		m.cancelRequest()
+		m.currentView = "files"
+		m.selectMode = false
+		m.jumping = false
+		m.finding = false
+	case "search":
+		m.currentView = "repositories"
+	case "refs", "tree", "finder":
+		m.cancelRequest()
+		m.currentView = "files"
+		m.cursor = 0
+	}
+	return m, nil
+}
+
+// failed shows err in a banner and keeps browsing, leaving the view that
+// could not be loaded
+func (m Model) failed(err error) (tea.Model, tea.Cmd) {
+	m.banner = err
+	switch {
+	case m.profile == nil:
+		m.currentView = "input"
+		m.inputting = true
+	case m.currentView == "files" && m.fileContents == nil:
+		m.currentView = "repositories"
+	case m.currentView == "fileContent", m.currentView == "refs", (m.currentView == "tree" || m.currentView == "finder") && m.tree == nil:
+		m.currentView = "files"
+		m.cursor = 0
+	}
+	return m, nil
+}
+
+// request runs fetch with a new context, cancelling the fetch still in flight.
+// Messages of cancelled fetches are dropped.
+func (m *Model) request(fetch func(ctx context.Context) tea.Msg) tea.Cmd {
+	m.cancelRequest()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	return func() tea.Msg {
+		msg := fetch(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return msg
+	}
+}
+
+// cancelRequest aborts the fetch in flight, if any
+func (m *Model) cancelRequest() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+// cacheTraced returns the provider to fetch with and the trace recording the age of cached responses
+func (m Model) cacheTraced() (forge.Provider, *forge.CacheTrace) {
+	trace := &forge.CacheTrace{}
+	if tracer, ok := m.provider.(forge.CacheTracer); ok {
+		return tracer.Traced(trace), trace
+	}
+	return m.provider, trace
+}
+
 // fetchProfile handles the profile fetching
-func (m Model) fetchProfile() tea.Msg {
-	profile, err := github_api.FetchGitHubProfile(m.githubID)
+func (m Model) fetchProfile(ctx context.Context) tea.Msg {
+	provider, trace := m.cacheTraced()
+	profile, err := provider.GetProfile(ctx, m.githubID)
 	if err != nil {
 		return err
 	}
+	// The profile README lives in the repository named after the user; it is
+	// shown when there is one
+	readme, _ := provider.GetReadme(ctx, profile.Login, profile.Login, "")
 
-	return *profile
+	staleAt, _ := trace.Oldest()
+	return profileMsg{profile: profile, readme: readme, staleAt: staleAt}
 }
 
 // fetchRepositories handles the profile repositories fetching
-func (m Model) fetchRepositories() tea.Msg {
-	repos, err := github_api.FetchRepositories(m.profile.Login)
+func (m Model) fetchRepositories(ctx context.Context) tea.Msg {
+	provider, trace := m.cacheTraced()
+	repos, err := provider.ListRepositories(ctx, m.profile.Login)
 	if err != nil {
 		return err
 	}
-	return repos
+	staleAt, _ := trace.Oldest()
+	return repositoriesMsg{repositories: repos, staleAt: staleAt}
 }
 
-// fetchRepositoryContents handles the profile repository contents fetching
-func (m Model) fetchRepositoryContents() tea.Msg {
-	contents, err := github_api.FetchRepositoryContents(m.profile.Login, m.selected["repository"], m.selected["path"])
-	if err != nil {
-		return err
+// fetchContents handles the repository contents fetching of a directory
+func (m Model) fetchContents(loc location) func(ctx context.Context) tea.Msg {
+	return func(ctx context.Context) tea.Msg {
+		provider, trace := m.cacheTraced()
+		contents, err := provider.ListContents(ctx, loc.owner, loc.repo, loc.ref, loc.path)
+		if err != nil {
+			return err
+		}
+		// The README is shown below the root listing when there is one
+		var readme *forge.File
+		var readmeErr error
+		if entry := forge.FindReadme(contents); loc.path == "" && entry != nil {
+			readme, readmeErr = provider.GetFile(ctx, loc.owner, loc.repo, loc.ref, "/"+entry.Name)
+		}
+		staleAt, _ := trace.Oldest()
+		return contentsMsg{location: loc, contents: contents, readme: readme, readmeErr: readmeErr, staleAt: staleAt}
 	}
-	return contents
 }
 
 // fetchFileContent handles the profile repository file content fetching
-func (m Model) fetchFileContent() tea.Msg {
-	content, err := github_api.FetchFileContent(m.profile.Login, m.selected["repository"], m.selected["path"]+"/"+m.selected["file"])
+func (m Model) fetchFileContent(ctx context.Context) tea.Msg {
+	provider, trace := m.cacheTraced()
+	file, err := provider.GetFile(ctx, m.selected["owner"], m.selected["repository"], m.selected["ref"], m.selected["path"]+"/"+m.selected["file"])
 	if err != nil {
 		return err
 	}
-	return content
+	staleAt, _ := trace.Oldest()
+	return fileContentMsg{file: file, staleAt: staleAt}
 }
 
 // searchRepositories handles the profile repositories search performing
-func (m Model) searchRepositories() tea.Msg {
-	repos, err := github_api.SearchRepositories(m.profile.Login, m.searchQuery)
+func (m Model) searchRepositories(ctx context.Context) tea.Msg {
+	provider, trace := m.cacheTraced()
+	repos, err := provider.SearchRepositories(ctx, m.profile.Login, m.searchQuery)
 	if err != nil {
 		return err
 	}
-	return repos
+	staleAt, _ := trace.Oldest()
+	return repositoriesMsg{repositories: repos, staleAt: staleAt}
 }
 
 // View handles the CLI global view
@@ -315,6 +606,7 @@ func (m Model) View() string {
 				config.HeaderStyle.Render("Git CLI Explorer"),
 				"\n",
 				config.CardStyle.Render(m.textInput.View()),
+				m.bannerView(),
 			),
 		)
 	case "profile", "repositories":
@@ -325,8 +617,12 @@ func (m Model) View() string {
 		return m.fileContentView()
 	case "search":
 		return m.searchView()
-	case "error":
-		return m.errorView()
+	case "refs":
+		return m.refsView()
+	case "tree":
+		return m.treeView()
+	case "finder":
+		return m.finderView()
 	default:
 		return config.DocStyle.Render(
 			config.CardStyle.Render(
@@ -360,6 +656,8 @@ func (m Model) getPaginationInfo() (currentPage, totalPages, startIdx, endIdx in
 		totalItems = len(m.repositories)
 	case "files":
 		totalItems = len(m.fileContents)
+	case "tree":
+		totalItems = len(m.treeRows())
 	default:
 		return 1, 1, 0, 0
 	}
@@ -381,6 +679,98 @@ func renderPagination(current, total int) string {
 	return config.PaginationInfoStyle.Render(fmt.Sprintf(config.PaginationStyle, current, total))
 }
 
+// rateLimitStatus renders the remaining API quota for the footers
+func (m Model) rateLimitStatus() string {
+	limiter, ok := m.provider.(forge.RateLimiter)
+	if !ok {
+		return ""
+	}
+	rate, ok := limiter.RateLimit()
+	if !ok {
+		return ""
+	}
+	style := config.FooterStyle
+	if rate.Remaining < rate.Limit/10 {
+		style = config.ErrorStyle
+	}
+	return style.Render(" • API quota: " + rate.String())
+}
+
+// staleIndicator renders when the data shown in view was cached, in offline mode
+func (m Model) staleIndicator(view string) string {
+	staleAt := m.staleAt[view]
+	tracer, ok := m.provider.(forge.CacheTracer)
+	if !ok || !tracer.Offline() || staleAt.IsZero() {
+		return ""
+	}
+	return config.StaleStyle.Render(fmt.Sprintf("stale as of %s", staleAt.Local().Format("Jan 2 15:04")))
+}
+
+// bannerView renders the error of the last failed fetch until it is dismissed with Esc
+func (m Model) bannerView() string {
+	if m.banner == nil {
+		return ""
+	}
+
+	lines := []string{
+		config.ErrorStyle.Render("⚠ " + errorTitle(m.banner)),
+		config.ValueStyle.Render(m.banner.Error()),
+	}
+	if hint := errorHint(m.banner); hint != "" {
+		lines = append(lines, hint)
+	}
+	var apiErr *forge.APIError
+	if errors.As(m.banner, &apiErr) && apiErr.DocumentationURL != "" {
+		lines = append(lines, config.FooterStyle.Render(apiErr.DocumentationURL))
+	}
+	lines = append(lines, config.FooterStyle.Render("Esc to dismiss"))
+	return config.BannerStyle.Render(strings.Join(lines, "\n")) + "\n\n"
+}
+
+// errorTitle names the kind of a fetch error
+func errorTitle(err error) string {
+	switch {
+	case errors.Is(err, forge.ErrNotFound):
+		return "Not found"
+	case errors.Is(err, forge.ErrUnauthorized):
+		return "Authentication failed"
+	case errors.Is(err, forge.ErrRateLimited):
+		return "Rate limit exceeded"
+	case errors.Is(err, forge.ErrForbidden):
+		return "Access denied"
+	case errors.Is(err, forge.ErrTooLarge):
+		return "Too large"
+	case errors.Is(err, forge.ErrNetwork):
+		return "Network error"
+	case errors.Is(err, forge.ErrNotCached):
+		return "Not available offline"
+	default:
+		return "Error"
+	}
+}
+
+// errorHint suggests how to get past a fetch error
+func errorHint(err error) string {
+	var apiErr *forge.APIError
+	if errors.As(err, &apiErr) && apiErr.SSOURL != "" {
+		return "Authorize your token for single sign-on at " + apiErr.SSOURL
+	}
+	switch {
+	case errors.Is(err, forge.ErrUnauthorized):
+		return "Check your token with 'ghexplorer auth status'"
+	case errors.Is(err, forge.ErrRateLimited):
+		return "Wait for the limit to reset or authenticate with 'ghexplorer auth login'"
+	case errors.Is(err, forge.ErrForbidden):
+		return "Your token may lack the scopes needed for this resource"
+	case errors.Is(err, forge.ErrNetwork):
+		return "Check your connection, or browse cached data with --offline"
+	case errors.Is(err, forge.ErrNotCached):
+		return "Browse it once online to make it available offline"
+	default:
+		return ""
+	}
+}
+
 // tabView handles the CLI tab view
 func (m Model) tabView() string {
 	doc := strings.Builder{}
@@ -396,6 +786,7 @@ func (m Model) tabView() string {
 	}
 	doc.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...))
 	doc.WriteString("\n\n")
+	doc.WriteString(m.bannerView())
 
 	// Render content based on active tab
 	switch m.activeTab {
@@ -408,7 +799,7 @@ func (m Model) tabView() string {
 	return config.DocStyle.Render(doc.String())
 }
 
-// overviewView handles the CLI overview view - simplified without README
+// overviewView handles the CLI overview view
 func (m Model) overviewView() string {
 	if m.profile == nil {
 		return config.CardStyle.Render("Loading profile...")
@@ -417,7 +808,7 @@ func (m Model) overviewView() string {
 	// Profile information section
 	profileInfo := lipgloss.JoinVertical(
 		lipgloss.Left,
-		config.HeaderStyle.Render("Profile Information"),
+		config.HeaderStyle.Render("Profile Information")+m.staleIndicator("profile"),
 		lipgloss.JoinHorizontal(lipgloss.Left, config.LabelStyle.Render("Name:"), config.ValueStyle.Render(helper.StringOrNA(m.profile.Name))),
 		lipgloss.JoinHorizontal(lipgloss.Left, config.LabelStyle.Render("Username:"), config.ValueStyle.Render(helper.StringOrNA(m.profile.Login))),
 		lipgloss.JoinHorizontal(lipgloss.Left, config.LabelStyle.Render("Bio:"), config.ValueStyle.Render(helper.StringOrNA(m.profile.Description))),
@@ -431,7 +822,17 @@ func (m Model) overviewView() string {
 		),
 	)
 
-	return config.ProfileCardStyle.Render(profileInfo)
+	hints := "\nTab to switch tabs • 'q' to quit"
+	if m.profileReadme != nil {
+		hints += " • ↑/↓ to scroll the README"
+	}
+	footer := config.FooterStyle.Render(hints) + m.rateLimitStatus()
+
+	sections := []string{config.ProfileCardStyle.Render(profileInfo)}
+	if readme := m.profileReadmeView(); readme != "" {
+		sections = append(sections, readme)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(sections, footer)...)
 }
 
 // repositoriesView handles the CLI repositories view
@@ -440,8 +841,16 @@ func (m Model) repositoriesView() string {
 
 	currentPage, totalPages, startIdx, endIdx := m.getPaginationInfo()
 
-	content.WriteString(config.HeaderStyle.Render("Repositories"))
+	content.WriteString(config.HeaderStyle.Render("Repositories") + m.staleIndicator("repositories"))
+	if arrangement := m.arrangement(); arrangement != "" {
+		content.WriteString(" " + config.DetailStyle.Render(arrangement))
+	}
 	content.WriteString("\n\n")
+	content.WriteString(m.filterView("repositories", len(m.repositories), len(m.listed)))
+	if len(m.repositories) == 0 && len(m.listed) > 0 {
+		content.WriteString(config.ValueStyle.Render(fmt.Sprintf("None of the %d repositories match the filters", len(m.listed))))
+		content.WriteString("\n")
+	}
 
 	// Display only the repositories for the current page
 	visibleRepos := m.repositories[startIdx:endIdx]
@@ -451,11 +860,17 @@ func (m Model) repositoriesView() string {
 			cursor = ">"
 		}
 
-		repoCard := lipgloss.JoinVertical(
-			lipgloss.Left,
-			config.RepositoryStyle.Render(helper.StringOrNA(repo.Name)),
+		lines := []string{
+			m.highlight("repositories", helper.StringOrNA(repo.Name), config.RepositoryStyle) + repositoryFlags(repo),
 			config.ValueStyle.Render(helper.StringOrNA(repo.Description)),
-		)
+		}
+		if details := repositoryDetails(repo); details != "" {
+			lines = append(lines, config.DetailStyle.Render(details))
+		}
+		if len(repo.Topics) > 0 {
+			lines = append(lines, config.DetailStyle.Render("#"+strings.Join(repo.Topics, " #")))
+		}
+		repoCard := lipgloss.JoinVertical(lipgloss.Left, lines...)
 
 		if startIdx+i == m.cursor {
 			repoCard = config.SelectedStyle.Render(repoCard)
@@ -470,13 +885,62 @@ func (m Model) repositoriesView() string {
 	content.WriteString("\n")
 	content.WriteString(renderPagination(currentPage, totalPages))
 
-	footer := config.FooterStyle.Render("\nPress Enter to view files • '/' to search • Tab to switch tabs • ←/→ to change pages")
+	footer := config.FooterStyle.Render("\nPress Enter to view files • '/' to filter • Ctrl+F to search • Tab to switch tabs • ←/→ to change pages"+
+		"\ns to sort • l/t to filter by language/topic • f/a to hide forks/archived • c to clear") + m.rateLimitStatus()
 
 	content.WriteString(footer)
 
 	return content.String()
 }
 
+// repositoryFlags renders the archived, fork and private badges of a repository
+func repositoryFlags(repo *forge.Repository) string {
+	var flags string
+	for _, flag := range repo.Flags() {
+		flags += " " + config.StaleStyle.Render("["+flag+"]")
+	}
+	return flags
+}
+
+// repositoryDetails summarizes the stars, forks, language, license and last update of a repository
+func repositoryDetails(repo *forge.Repository) string {
+	var parts []string
+	if repo.Stars > 0 || repo.Forks > 0 {
+		parts = append(parts, fmt.Sprintf("★ %d", repo.Stars), fmt.Sprintf("⑂ %d", repo.Forks))
+	}
+	if repo.Language != "" {
+		parts = append(parts, repo.Language)
+	}
+	if license := repo.LicenseName(); license != "" {
+		parts = append(parts, license)
+	}
+	if !repo.UpdatedAt.IsZero() {
+		parts = append(parts, "updated "+repo.UpdatedAt.Local().Format("Jan 2, 2006"))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// entryStyle returns the name style, icon and details shown for a directory
+// entry. Symlink targets and submodule URLs left out of listings are not shown.
+func entryStyle(file *forge.FileInfo) (lipgloss.Style, string, string) {
+	switch {
+	case file.Type == "dir":
+		return config.FolderStyle, "📁", ""
+	case file.Type == "symlink" && file.Target == "":
+		return config.FileStyle, "🔗", ""
+	case file.Type == "symlink":
+		return config.FileStyle, "🔗", " → " + file.Target
+	case file.Type == "submodule" && file.SubmoduleGitURL == "":
+		return config.FolderStyle, "📦", ""
+	case file.Type == "submodule":
+		return config.FolderStyle, "📦", " @ " + file.SubmoduleGitURL
+	case file.SizeUnknown:
+		return config.FileStyle, "📄", ""
+	default:
+		return config.FileStyle, "📄", "  " + helper.FormatSize(file.Size)
+	}
+}
+
 // filesView handles the CLI files view
 func (m Model) filesView() string {
 	var content strings.Builder
@@ -485,12 +949,15 @@ func (m Model) filesView() string {
 
 	header := lipgloss.JoinVertical(
 		lipgloss.Left,
-		config.HeaderStyle.Render(fmt.Sprintf("Repository: %s", helper.StringOrNA(m.selected["repository"]))),
+		config.HeaderStyle.Render(fmt.Sprintf("Repository: %s", helper.StringOrNA(m.selected["repository"])))+m.staleIndicator("files"),
+		config.ValueStyle.Render(fmt.Sprintf("Ref: %s", refName(m.selected["ref"]))),
 		config.ValueStyle.Render(fmt.Sprintf("Path: %s", helper.StringOrNA(m.selected["path"]))),
 	)
 
+	content.WriteString(m.bannerView())
 	content.WriteString(config.CardStyle.Render(header))
 	content.WriteString("\n\n")
+	content.WriteString(m.filterView("files", len(m.fileContents), len(m.listedFiles)))
 
 	// Display only the files for the current page
 	visibleFiles := m.fileContents[startIdx:endIdx]
@@ -500,21 +967,13 @@ func (m Model) filesView() string {
 			cursor = ">"
 		}
 
-		var fileNameStyle lipgloss.Style
-		var icon string
-		if file.Type == "dir" {
-			fileNameStyle = config.FolderStyle
-			icon = "📁"
-		} else {
-			fileNameStyle = config.FileStyle
-			icon = "📄"
-		}
-
+		fileNameStyle, icon, details := entryStyle(file)
 		fileCard := lipgloss.JoinHorizontal(
 			lipgloss.Left,
 			icon,
 			" ",
-			fileNameStyle.Render(helper.StringOrNA(file.Name)),
+			m.highlight("files", helper.StringOrNA(file.Name), fileNameStyle),
+			config.DetailStyle.Render(details),
 		)
 
 		if startIdx+i == m.cursor {
@@ -530,41 +989,109 @@ func (m Model) filesView() string {
 	content.WriteString("\n")
 	content.WriteString(renderPagination(currentPage, totalPages))
 
-	footer := config.FooterStyle.Render("\nPress Enter to view content • Esc to go back • ←/→ to change pages")
+	footer := config.FooterStyle.Render("\nPress Enter to open files, folders, symlinks and submodules • '/' to filter • r to switch ref • Esc to go back • ←/→ to change pages") + m.rateLimitStatus()
 
+	if readme := m.repoReadmeView(); readme != "" {
+		footer = lipgloss.JoinVertical(lipgloss.Left, readme, footer)
+	}
 	content.WriteString(footer)
 
 	return content.String()
 }
 
+// fileText returns the text displayed for file. Text that is not UTF-8 is read
+// as Latin-1.
+func fileText(file *forge.File) string {
+	if file.Binary {
+		return fmt.Sprintf("Binary file (%s) not shown", helper.FormatSize(file.Size))
+	}
+	content := file.Content
+	if file.Truncated {
+		content = dropCutRune(content)
+	}
+	if utf8.Valid(content) {
+		return string(content)
+	}
+	runes := make([]rune, len(content))
+	for i, b := range content {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// dropCutRune drops the start of a multi-byte rune cut at the end of data
+func dropCutRune(data []byte) []byte {
+	start := len(data) - 1
+	for start > 0 && start > len(data)-utf8.UTFMax && !utf8.RuneStart(data[start]) {
+		start--
+	}
+	if start >= 0 && !utf8.FullRune(data[start:]) {
+		return data[:start]
+	}
+	return data
+}
+
+// fileMetadata renders the size, blob SHA and encoding of the open file
+func (m Model) fileMetadata() string {
+	if m.file == nil {
+		return ""
+	}
+	parts := []string{helper.FormatSize(m.file.Size)}
+	if m.file.SHA != "" {
+		parts = append(parts, "sha "+m.file.SHA[:min(len(m.file.SHA), 7)])
+	}
+	if m.file.Encoding != "" {
+		parts = append(parts, m.file.Encoding)
+	}
+	if m.file.Binary {
+		parts = append(parts, "binary")
+	}
+	metadata := config.DetailStyle.Render(strings.Join(parts, " • "))
+	if m.file.Truncated {
+		metadata += config.StaleStyle.Render(fmt.Sprintf("  showing the first %s", helper.FormatSize(int64(len(m.file.Content)))))
+	}
+	return metadata
+}
+
 // fileContentView handles the CLI fileContent view
 func (m Model) fileContentView() string {
 	header := config.CardStyle.Render(
 		lipgloss.JoinVertical(
 			lipgloss.Left,
-			config.HeaderStyle.Render("File Content"),
+			config.HeaderStyle.Render("File Content")+m.staleIndicator("fileContent"),
 			config.ValueStyle.Render(helper.StringOrNA(m.selected["file"])),
+			m.fileMetadata(),
+			config.FooterStyle.Render(m.provider.FileHTMLURL(m.selected["owner"], m.selected["repository"], m.selected["ref"], m.selected["path"]+"/"+m.selected["file"])),
 		),
 	)
 
-	footer := config.FooterStyle.Render("\nPress Esc to go back • Ctrl+A to select all • Ctrl+C to copy • Ctrl+D to deselect • ↑/↓ to scroll")
-
-	var styledContent string
-	if m.selectMode {
-		before := m.fileContent[:m.selectStart]
-		selected := config.SelectedStyle.Render(m.fileContent[m.selectStart:m.selectEnd])
-		after := m.fileContent[m.selectEnd:]
-		styledContent = before + selected + after
-		return lipgloss.JoinVertical(
-			lipgloss.Left,
-			header,
-			"\n",
-			config.CardStyle.Render(styledContent),
-			footer,
-		)
-	} else {
-		styledContent = m.fileContent
+	var hints string
+	switch {
+	case m.finding:
+		hints = "\nPress Enter to keep the matches • Esc to cancel • Ctrl+R to match regular expressions • Ctrl+T to match case"
+	case m.selectMode:
+		hints = "\nPress Esc to deselect • ←↑↓→ or hjkl to move • v/V to select characters/lines • y or Ctrl+C to copy"
+	default:
+		hints = "\nPress Esc to go back • v/V to select characters/lines • Ctrl+A to select all • ↑/↓ to scroll"
+		if m.noWrap {
+			hints += " • ←/→ to scroll sideways • w to wrap lines"
+		} else {
+			hints += " • w to stop wrapping lines"
+		}
+		hints += " • : or Ctrl+G to go to a line • / to find"
+		if len(m.matches) > 0 {
+			hints += " • n/N for the next/previous match"
+		}
+		switch {
+		case m.markdown != "" && m.showSource:
+			hints += " • m to show rendered"
+		case m.markdown != "":
+			hints += " • m to show source"
+		}
 	}
+	footer := m.positionView() + config.FooterStyle.Render(hints) + m.rateLimitStatus()
+
+	styledContent := m.renderedContent()
 
 	// Set the viewport content if it hasn't been set
 	if m.viewport.Height == 0 {
@@ -600,19 +1127,3 @@ func (m Model) searchView() string {
 		),
 	)
 }
-
-// errorView handles the CLI error view
-func (m Model) errorView() string {
-	return config.DocStyle.Render(
-		config.CardStyle.Render(
-			lipgloss.JoinVertical(
-				lipgloss.Left,
-				config.ErrorStyle.Render("Error Occurred"),
-				"",
-				config.ValueStyle.Render(m.errorMessage),
-				"",
-				config.FooterStyle.Render("Press 'q' key to quit"),
-			),
-		),
-	)
-}
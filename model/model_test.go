@@ -0,0 +1,887 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"ghexplorer/forge/forgetest"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestProvider returns a fake provider holding one profile with one repository
+func newTestProvider() *forgetest.Fake {
+	fake := forgetest.NewFake()
+	fake.AddProfile(&forge.Profile{Login: "octocat", Name: "The Octocat"})
+	fake.AddRepository("octocat", &forge.Repository{Name: "Hello-World", Description: "My first repository", Stars: 80, Forks: 9, Language: "C", Topics: []string{"octocat"}, Fork: true})
+	fake.AddFile("octocat", "Hello-World", "README", "Hello World!")
+	fake.AddFile("octocat", "Hello-World", "docs/guide.md", "# Guide")
+	return fake
+}
+
+// update feeds msg to m and then the messages produced by the returned commands,
+// as the Bubble Tea runtime would. Commands that do not answer promptly, such as
+// the cursor blink ticks of the text input, are dropped.
+func update(m Model, msg tea.Msg) Model {
+	for msg != nil {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		if cmd == nil {
+			return m
+		}
+
+		done := make(chan tea.Msg, 1)
+		go func() { done <- cmd() }()
+		select {
+		case msg = <-done:
+		case <-time.After(50 * time.Millisecond):
+			return m
+		}
+	}
+	return m
+}
+
+// key builds the message of a key press
+func key(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "left":
+		return tea.KeyMsg{Type: tea.KeyLeft}
+	case "right":
+		return tea.KeyMsg{Type: tea.KeyRight}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestBrowse(t *testing.T) {
+	m := InitialModel(newTestProvider(), "octocat")
+
+	m = update(m, key("enter"))
+	assert.Equal(t, "The Octocat", m.profile.Name)
+	assert.Equal(t, "repositories", m.currentView)
+	assert.Len(t, m.repositories, 1)
+	assert.Contains(t, m.repositoriesView(), "★ 80 • ⑂ 9 • C")
+	assert.Contains(t, m.repositoriesView(), "#octocat")
+	assert.Contains(t, m.repositoriesView(), "[fork]")
+
+	m = update(m, key("enter"))
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "Hello-World", m.selected["repository"])
+	assert.Len(t, m.fileContents, 2)
+	assert.Equal(t, "README", m.fileContents[0].Name)
+
+	m = update(m, key("enter"))
+	assert.Equal(t, "fileContent", m.currentView)
+	assert.Equal(t, "Hello World!", m.fileContent)
+
+	m = update(m, key("esc"))
+	assert.Equal(t, "files", m.currentView)
+
+	m = update(m, key("down"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "/docs", m.selected["path"])
+	assert.Len(t, m.fileContents, 1)
+	assert.Equal(t, "guide.md", m.fileContents[0].Name)
+
+	m = update(m, key("esc"))
+	assert.Equal(t, "", m.selected["path"])
+	assert.Len(t, m.fileContents, 2)
+}
+
+func TestSearch(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddRepository("octocat", &forge.Repository{Name: "Spoon-Knife"})
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	assert.Len(t, m.repositories, 2)
+
+	m = update(m, key("ctrl+f"))
+	assert.Equal(t, "search", m.currentView)
+	for _, r := range "spoon" {
+		m = update(m, key(string(r)))
+	}
+	m = update(m, key("enter"))
+	assert.Equal(t, "repositories", m.currentView)
+	assert.Len(t, m.repositories, 1)
+	assert.Equal(t, "Spoon-Knife", m.repositories[0].Name)
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddRepository("octocat", &forge.Repository{Name: "Spoon-Knife"})
+	fake.AddRepository("octocat", &forge.Repository{Name: "linguist"})
+	fake.AddFile("octocat", "linguist", "Gemfile", "source 'https://rubygems.org'")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+
+	m = update(m, key("/"))
+	assert.True(t, m.filtering)
+	for _, r := range "sqk" {
+		m = update(m, key(string(r)))
+	}
+	assert.Empty(t, m.repositories)
+	m = update(m, key("backspace"))
+	m = update(m, key("backspace"))
+	m = update(m, key("k"))
+	assert.Equal(t, []string{"Spoon-Knife"}, repositoryNames(m))
+	assert.Contains(t, m.repositoriesView(), "Filter: sk")
+	assert.Contains(t, m.repositoriesView(), "1 of 3")
+
+	// Letters are typed into the filter rather than sorting or quitting
+	m = update(m, key("i"))
+	assert.Equal(t, "ski", m.filters["repositories"])
+	assert.Equal(t, "", m.repoSort)
+
+	m = update(m, key("backspace"))
+	m = update(m, key("backspace"))
+	m = update(m, key("backspace"))
+	m = update(m, key("l"))
+	assert.Equal(t, []string{"linguist", "Hello-World"}, repositoryNames(m))
+
+	m = update(m, key("enter"))
+	assert.False(t, m.filtering)
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "linguist", m.selected["repository"])
+
+	m = update(m, key("esc"))
+	assert.Equal(t, "repositories", m.currentView)
+	assert.Equal(t, []string{"linguist", "Hello-World"}, repositoryNames(m))
+	m = update(m, key("esc"))
+	assert.Equal(t, "repositories", m.currentView)
+	assert.Len(t, m.repositories, 3)
+
+	m = update(m, key("enter"))
+	assert.Equal(t, "Hello-World", m.selected["repository"])
+	m = update(m, key("/"))
+	m = update(m, key("d"))
+	m = update(m, key("o"))
+	assert.Len(t, m.fileContents, 1)
+	m = update(m, key("enter"))
+	assert.Equal(t, "/docs", m.selected["path"])
+	assert.Equal(t, "", m.filters["files"])
+	assert.Len(t, m.fileContents, 1)
+}
+
+func TestRefs(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddBranch("octocat", "Hello-World", "master", "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d")
+	fake.AddBranch("octocat", "Hello-World", "test", "b3cbd5bbd7e81436d2eee04537ea2b4c0cad4cdf")
+	fake.AddTag("octocat", "Hello-World", "v1.0", "c5b97d5ae6c19d5c5df71a34c7fbeeda2479ccbc")
+	fake.AddFile("octocat", "Hello-World@v1.0", "CHANGELOG", "v1.0")
+	fake.AddFile("octocat", "Hello-World@553c207", "README", "Old")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Contains(t, m.filesView(), "Ref: default branch")
+
+	m = update(m, key("r"))
+	assert.Equal(t, "refs", m.currentView)
+	assert.Len(t, m.refs, 3)
+	assert.Contains(t, m.refsView(), "c5b97d5")
+
+	m = update(m, key("v"))
+	m = update(m, key("1"))
+	assert.Len(t, m.refMatches(), 1)
+	m = update(m, key("enter"))
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "v1.0", m.selected["ref"])
+	assert.Equal(t, "CHANGELOG", m.fileContents[0].Name)
+	assert.Contains(t, m.filesView(), "Ref: v1.0")
+
+	m = update(m, key("enter"))
+	assert.Equal(t, "v1.0", m.fileContent)
+	m = update(m, key("esc"))
+
+	// A revision matching no branch or tag is browsed as typed
+	m = update(m, key("r"))
+	for _, r := range "553c207" {
+		m = update(m, key(string(r)))
+	}
+	assert.Empty(t, m.refMatches())
+	m = update(m, key("enter"))
+	assert.Equal(t, "553c207", m.selected["ref"])
+	assert.Equal(t, "README", m.fileContents[0].Name)
+
+	m = update(m, key("r"))
+	for _, r := range "nope" {
+		m = update(m, key(string(r)))
+	}
+	m = update(m, key("enter"))
+	assert.Error(t, m.banner)
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "553c207", m.selected["ref"])
+}
+
+func TestTree(t *testing.T) {
+	m := update(InitialModel(newTestProvider(), "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	m = update(m, key("t"))
+	assert.Equal(t, "tree", m.currentView)
+	rows := m.treeRows()
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, "docs", rows[0].entry.Name)
+		assert.Equal(t, "README", rows[1].entry.Name)
+	}
+	assert.Contains(t, m.treeView(), "▸")
+
+	// Folders expand inline
+	m = update(m, key("enter"))
+	rows = m.treeRows()
+	if assert.Len(t, rows, 3) {
+		assert.Equal(t, "guide.md", rows[1].entry.Name)
+		assert.Equal(t, 1, rows[1].depth)
+	}
+	assert.Contains(t, m.treeView(), "▾")
+
+	m = update(m, key("down"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "fileContent", m.currentView)
+	assert.Equal(t, "# Guide", m.fileContent)
+	assert.Equal(t, "/docs", m.selected["path"])
+
+	// The tree is kept, expanded, for the same repository and ref
+	m = update(m, key("esc"))
+	m = update(m, key("t"))
+	assert.Len(t, m.treeRows(), 3)
+	m = update(m, key("t"))
+	assert.Equal(t, "files", m.currentView)
+}
+
+func TestFinder(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddFile("octocat", "Hello-World", "docs/api/index.md", "# API")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	m = update(m, key("ctrl+p"))
+	assert.Equal(t, "finder", m.currentView)
+	assert.Len(t, m.finderMatches, 3)
+
+	for _, r := range "gui" {
+		m = update(m, key(string(r)))
+	}
+	if assert.Len(t, m.finderMatches, 1) {
+		assert.Contains(t, m.finderView(), "guide.md")
+	}
+
+	// The file opens in its directory, which Esc goes back to
+	m = update(m, key("enter"))
+	assert.Equal(t, "fileContent", m.currentView)
+	assert.Equal(t, "# Guide", m.fileContent)
+	assert.Equal(t, "/docs", m.selected["path"])
+	m = update(m, key("esc"))
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "/docs", m.selected["path"])
+	m = update(m, key("esc"))
+	assert.Equal(t, "", m.selected["path"])
+
+	m = update(m, key("ctrl+p"))
+	m = update(m, key("x"))
+	m = update(m, key("q"))
+	assert.Equal(t, "xq", m.filters["finder"])
+	assert.Empty(t, m.finderMatches)
+	m = update(m, key("esc"))
+	assert.Equal(t, "files", m.currentView)
+}
+
+func TestArrangeRepositories(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddRepository("octocat", &forge.Repository{Name: "Spoon-Knife", Stars: 200, Language: "HTML", Archived: true})
+	fake.AddRepository("octocat", &forge.Repository{Name: "linguist", Stars: 100, Language: "Ruby", Topics: []string{"octocat"}})
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("tab"))
+	assert.Len(t, m.repositories, 3)
+
+	m = update(m, key("s"))
+	assert.Equal(t, []string{"Spoon-Knife", "linguist", "Hello-World"}, repositoryNames(m))
+	m = update(m, key("s"))
+	assert.Equal(t, "name", m.repoSort)
+	assert.Equal(t, []string{"Hello-World", "linguist", "Spoon-Knife"}, repositoryNames(m))
+
+	m = update(m, key("a"))
+	assert.Equal(t, []string{"Hello-World", "linguist"}, repositoryNames(m))
+	m = update(m, key("f"))
+	assert.Equal(t, []string{"linguist"}, repositoryNames(m))
+	assert.Contains(t, m.repositoriesView(), "sorted by name • hide-forks, hide-archived")
+
+	m = update(m, key("c"))
+	m = update(m, key("t"))
+	assert.Equal(t, "octocat", m.repoFilter.Topic)
+	assert.Equal(t, []string{"Hello-World", "linguist"}, repositoryNames(m))
+	m = update(m, key("l"))
+	assert.Equal(t, "C", m.repoFilter.Language)
+	assert.Equal(t, []string{"Hello-World"}, repositoryNames(m))
+	m = update(m, key("l"))
+	assert.Empty(t, repositoryNames(m))
+	assert.Contains(t, m.repositoriesView(), "None of the 3 repositories match the filters")
+}
+
+func repositoryNames(m Model) []string {
+	var names []string
+	for _, repo := range m.repositories {
+		names = append(names, repo.Name)
+	}
+	return names
+}
+
+func TestErrorBanner(t *testing.T) {
+	fake := newTestProvider()
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+
+	fake.Err = forge.ErrNotCached
+	m = update(m, key("enter"))
+	assert.Equal(t, "repositories", m.currentView)
+	assert.ErrorIs(t, m.banner, forge.ErrNotCached)
+
+	m = update(m, key("esc"))
+	assert.Nil(t, m.banner)
+	assert.Equal(t, "repositories", m.currentView)
+
+	fake.Err = nil
+	m = update(m, key("enter"))
+	m = update(m, key("down"))
+	fake.Err = &forge.APIError{Kind: forge.ErrNotFound, What: "repository contents", Status: "404 Not Found"}
+	m = update(m, key("enter"))
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "", m.selected["path"])
+	assert.Len(t, m.fileContents, 2)
+	assert.Contains(t, m.View(), "Not found")
+
+	m = update(m, key("esc"))
+	assert.Equal(t, "files", m.currentView)
+	m = update(m, key("esc"))
+	assert.Equal(t, "repositories", m.currentView)
+}
+
+func TestBackCancelsFetch(t *testing.T) {
+	fake := newTestProvider()
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+
+	fake.Hold = make(chan struct{})
+	next, fetch := m.Update(key("enter"))
+	m = next.(Model)
+	assert.Equal(t, "fileContent", m.currentView)
+
+	m = update(m, key("esc"))
+	assert.Equal(t, "files", m.currentView)
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- fetch() }()
+	select {
+	case msg := <-done:
+		assert.Nil(t, msg)
+	case <-time.After(time.Second):
+		t.Fatal("fetch was not cancelled")
+	}
+}
+
+func TestResizeWhileFileLoads(t *testing.T) {
+	fake := newTestProvider()
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+
+	fake.Hold = make(chan struct{})
+	defer close(fake.Hold)
+	next, _ := m.Update(key("enter"))
+	m = next.(Model)
+	assert.Equal(t, "fileContent", m.currentView)
+	assert.NotPanics(t, func() {
+		m = update(m, tea.WindowSizeMsg{Width: 100, Height: 40})
+	})
+	assert.Equal(t, "fileContent", m.currentView)
+}
+
+func TestBinaryFile(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddFile("octocat", "Hello-World", "LOGO.bin", "\x00\x01\x02")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "LOGO.bin", m.fileContents[0].Name)
+
+	m = update(m, key("enter"))
+	assert.Equal(t, "fileContent", m.currentView)
+	assert.True(t, m.file.Binary)
+	assert.Equal(t, "Binary file (3 B) not shown", m.fileContent)
+
+	m = update(m, key("ctrl+a"))
+	assert.False(t, m.selectMode)
+}
+
+// enter moves the cursor to the named entry of the files view and opens it
+func enter(t *testing.T, m Model, name string) Model {
+	t.Helper()
+	for i, entry := range m.fileContents {
+		if entry.Name == name {
+			m.cursor = i
+			return update(m, key("enter"))
+		}
+	}
+	t.Fatalf("%s is not listed", name)
+	return m
+}
+
+func TestHighlighting(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	fake := newTestProvider()
+	fake.AddFile("octocat", "Hello-World", "Hello.go", "package main\n\nfunc main() {}\n")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "Hello.go", m.fileContents[0].Name)
+
+	m = update(m, key("enter"))
+	if assert.Len(t, m.highlighted, 4) {
+		assert.Contains(t, m.highlighted[0], "\x1b[")
+	}
+	assert.Contains(t, m.renderedContent(), "\x1b[")
+	assert.Len(t, m.highlights, 1)
+
+	// The selection is shown in plain text, the other lines stay highlighted
+	for _, k := range []string{"v", "l", "l", "l", "l", "l"} {
+		m = update(m, key(k))
+	}
+	assert.Equal(t, "packag", m.selectedText())
+	assert.Contains(t, m.viewport.View(), config.SelectedStyle.Render("packag"))
+	assert.Contains(t, m.viewport.View(), m.highlighted[2])
+	m = update(m, key("j"))
+	m = update(m, key("j"))
+	assert.Equal(t, "package main\n\nfunc m", m.selectedText())
+
+	// Plain text is not highlighted
+	m = update(m, key("esc"))
+	m = update(m, key("esc"))
+	m = update(m, key("down"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "Hello World!", m.fileContent)
+	assert.Nil(t, m.highlighted)
+	assert.Equal(t, "Hello World!", m.renderedContent())
+}
+
+func TestMarkdown(t *testing.T) {
+	fake := newTestProvider()
+	source := "# Notes\n\nSee the [docs](https://example.com/docs).\n"
+	fake.AddFile("octocat", "Hello-World", "NOTES.md", source)
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "NOTES.md", m.fileContents[0].Name)
+
+	m = update(m, key("enter"))
+	assert.Contains(t, m.renderedContent(), "Notes\n"+strings.Repeat("═", 80))
+	assert.Contains(t, m.renderedContent(), "[1] https://example.com/docs")
+	assert.Contains(t, m.fileContentView(), "m to show source")
+
+	// The source is shown on demand
+	m = update(m, key("m"))
+	assert.Equal(t, source, m.renderedContent())
+	assert.Contains(t, m.fileContentView(), "m to show rendered")
+	m = update(m, key("m"))
+
+	// The document is wrapped again when the terminal is resized
+	m = update(m, tea.WindowSizeMsg{Width: 44, Height: 30})
+	assert.Contains(t, m.renderedContent(), "Notes\n"+strings.Repeat("═", 40)+"\n")
+
+	// Other files have no rendered view
+	m = update(m, key("esc"))
+	m = update(m, key("down"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "README", m.selected["file"])
+	assert.Empty(t, m.markdown)
+	m = update(m, key("m"))
+	assert.Equal(t, "Hello World!", m.renderedContent())
+}
+
+func TestReadmes(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddFile("octocat", "octocat", "README.md", "# Hi there\n\n"+strings.Repeat("- a line\n", 30))
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("tab"))
+	m = update(m, key("tab"))
+	assert.Equal(t, "profile", m.currentView)
+	if assert.NotNil(t, m.profileReadme) {
+		assert.Contains(t, m.overviewView(), "Hi there")
+		assert.Contains(t, m.overviewView(), "↑/↓ to scroll the README")
+	}
+
+	// The profile README scrolls
+	m = update(m, key("down"))
+	assert.Equal(t, 1, m.readme.YOffset)
+
+	// The README of a repository is shown below its root listing only
+	m = update(m, key("tab"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "Hello World!", m.repoReadmeText)
+	assert.Contains(t, m.filesView(), "Hello World!")
+	m = update(m, key("down"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "/docs", m.selected["path"])
+	assert.Empty(t, m.repoReadmeText)
+	assert.NotContains(t, m.filesView(), "Hello World!")
+
+	// A README that cannot be fetched is reported
+	fake.AddSymlink("octocat", "Hello-World", "README.md", "missing.md")
+	m = update(m, key("esc"))
+	assert.Equal(t, "", m.selected["path"])
+	assert.Nil(t, m.repoReadme)
+	assert.ErrorContains(t, m.banner, "could not fetch the README")
+}
+
+func TestLinks(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddSymlink("octocat", "Hello-World", "GUIDE", "docs/guide.md")
+	fake.AddSymlink("octocat", "Hello-World", "manual", "./docs/")
+	fake.AddSubmodule("octocat", "Hello-World", "lib", "../Spoon-Knife.git")
+	fake.AddFile("octocat", "Spoon-Knife", "index.html", "<html></html>")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Len(t, m.fileContents, 5)
+	assert.Contains(t, m.View(), "→ docs/guide.md")
+
+	m = enter(t, m, "GUIDE")
+	assert.Equal(t, "fileContent", m.currentView)
+	assert.Equal(t, "/docs", m.selected["path"])
+	assert.Equal(t, "guide.md", m.selected["file"])
+	assert.Equal(t, "# Guide", m.fileContent)
+
+	m = update(m, key("esc"))
+	m = update(m, key("esc"))
+	assert.Equal(t, "", m.selected["path"])
+
+	m = enter(t, m, "manual")
+	assert.Equal(t, "/docs", m.selected["path"])
+	m = update(m, key("esc"))
+
+	m = enter(t, m, "lib")
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "Spoon-Knife", m.selected["repository"])
+	assert.Equal(t, "index.html", m.fileContents[0].Name)
+
+	m = update(m, key("esc"))
+	assert.Equal(t, "files", m.currentView)
+	assert.Equal(t, "Hello-World", m.selected["repository"])
+	m = update(m, key("esc"))
+	assert.Equal(t, "repositories", m.currentView)
+}
+
+func TestDescribedLinks(t *testing.T) {
+	fake := newTestProvider()
+	fake.DescribeLinks = true
+	fake.AddSymlink("octocat", "Hello-World", "GUIDE", "guide")
+	fake.AddSymlink("octocat", "Hello-World", "guide", "docs/guide.md")
+	fake.AddSubmodule("octocat", "Hello-World", "lib", "../Spoon-Knife.git")
+	fake.Links["octocat/Hello-World/lib"].SHA = "5e7a"
+	fake.AddFile("octocat", "Spoon-Knife", "index.html", "<html></html>")
+	fake.AddFile("octocat", "Spoon-Knife@5e7a", "old.html", "<html></html>")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.NotContains(t, m.View(), "→ docs")
+
+	// Targets are found when the links are opened, chained links included
+	m = enter(t, m, "GUIDE")
+	assert.Equal(t, "/docs", m.selected["path"])
+	assert.Equal(t, "# Guide", m.fileContent)
+	m = update(m, key("esc"))
+	m = update(m, key("esc"))
+
+	// Submodules open at the commit recorded by the parent
+	m = enter(t, m, "lib")
+	assert.Equal(t, "Spoon-Knife", m.selected["repository"])
+	assert.Equal(t, "5e7a", m.selected["ref"])
+	assert.Equal(t, "old.html", m.fileContents[0].Name)
+}
+
+func TestEntrySizes(t *testing.T) {
+	_, _, details := entryStyle(&forge.FileInfo{Name: "empty", Type: "file"})
+	assert.Equal(t, "  0 B", details)
+	_, _, details = entryStyle(&forge.FileInfo{Name: "main.go", Type: "file", SizeUnknown: true})
+	assert.Empty(t, details)
+}
+
+func TestResolveLink(t *testing.T) {
+	assert.Equal(t, "/docs/guide.md", resolveLink("", "docs/guide.md"))
+	assert.Equal(t, "/src/util", resolveLink("/src/cmd", "../util"))
+	assert.Equal(t, "/README", resolveLink("/a/b", "/README"))
+	assert.Equal(t, "", resolveLink("/a", "../../.."))
+}
+
+func TestFileViewer(t *testing.T) {
+	fake := newTestProvider()
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	lines[2] = strings.Repeat("0123456789", 10)
+	fake.AddFile("octocat", "Hello-World", "LONG.txt", strings.Join(lines, "\n"))
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "LONG.txt", m.fileContents[0].Name)
+
+	// Lines are numbered and wrapped to the 75 columns right of the gutter
+	m = update(m, key("enter"))
+	assert.Contains(t, m.fileContentView(), " 1 │ line 1")
+	assert.Contains(t, m.fileContentView(), "line 1 of 30")
+	assert.Equal(t, []int{0, 1, 2, 4}, m.lineStarts[:4])
+
+	// Go to a line, or as far as the viewport scrolls
+	for _, k := range []string{":", "5", "enter"} {
+		m = update(m, key(k))
+	}
+	assert.Equal(t, 5, m.topLine())
+	assert.Contains(t, m.fileContentView(), "line 5 of 30")
+	m = update(m, key("ctrl+g"))
+	m = update(m, key("9"))
+	assert.Contains(t, m.fileContentView(), "Go to line: 9")
+	m = update(m, key("9"))
+	m = update(m, key("enter"))
+	assert.False(t, m.jumping)
+	assert.Equal(t, 11, m.topLine())
+
+	// Unwrapped lines scroll sideways
+	m = update(m, key("w"))
+	assert.Equal(t, []int{0, 1, 2, 3}, m.lineStarts[:4])
+	m = m.scrollToLine(1)
+	m = update(m, key("right"))
+	assert.Equal(t, config.ScrollStep, m.xOffset)
+	assert.Contains(t, m.viewport.View(), " 3 │ 89012345")
+	for range 5 {
+		m = update(m, key("right"))
+	}
+	assert.Equal(t, 25, m.xOffset)
+	m = update(m, key("left"))
+	assert.Equal(t, 25-config.ScrollStep, m.xOffset)
+
+	// Wrapping again shows the lines from their start
+	m = update(m, key("w"))
+	assert.Equal(t, 0, m.xOffset)
+	assert.Contains(t, m.viewport.View(), " 3 │ 0123456789")
+}
+
+func TestSkipColumns(t *testing.T) {
+	assert.Equal(t, "lo", skipColumns("hello", 3))
+	assert.Equal(t, "\x1b[31mlo\x1b[0m", skipColumns("\x1b[31mhello\x1b[0m", 3))
+	assert.Equal(t, " 界", skipColumns("世界", 1))
+	assert.Equal(t, "", skipColumns("hi", 5))
+}
+
+func TestFind(t *testing.T) {
+	fake := newTestProvider()
+	lines := make([]string, 40)
+	for i := range lines {
+		lines[i] = "x"
+	}
+	lines[0], lines[2], lines[39] = "Foo bar", "foo foo", "the end: foo"
+	fake.AddFile("octocat", "Hello-World", "FIND.txt", strings.Join(lines, "\n"))
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "FIND.txt", m.fileContents[0].Name)
+	m = update(m, key("enter"))
+
+	// Matches are found as the text is typed, ignoring case
+	for _, k := range []string{"/", "f", "o", "o"} {
+		m = update(m, key(k))
+	}
+	assert.True(t, m.finding)
+	assert.Len(t, m.matches, 4)
+	assert.Contains(t, m.fileContentView(), "Find: foo")
+	assert.Contains(t, m.fileContentView(), "match 1 of 4")
+	m = update(m, key("enter"))
+	assert.False(t, m.finding)
+
+	// n and N go through the matches, scrolling to them
+	m = update(m, key("n"))
+	assert.Equal(t, fileMatch{line: 2, start: 0, end: 3}, m.matches[m.matchIndex])
+	m = update(m, key("n"))
+	m = update(m, key("n"))
+	assert.Equal(t, 3, m.matchIndex)
+	assert.Greater(t, m.topLine(), 20)
+	assert.Contains(t, m.viewport.View(), "40 │ the end: foo")
+	m = update(m, key("n"))
+	assert.Equal(t, 0, m.matchIndex)
+	assert.Equal(t, 1, m.topLine())
+	m = update(m, key("N"))
+	assert.Equal(t, 3, m.matchIndex)
+	assert.Contains(t, m.fileContentView(), "match 4 of 4")
+
+	// Esc forgets the search before going back
+	m = update(m, key("esc"))
+	assert.Equal(t, "fileContent", m.currentView)
+	assert.Empty(t, m.matches)
+
+	// The case is matched on demand
+	for _, k := range []string{"/", "ctrl+t", "F", "o", "o"} {
+		m = update(m, key(k))
+	}
+	assert.Equal(t, []fileMatch{{line: 0, start: 0, end: 3}}, m.matches)
+	assert.Contains(t, m.fileContentView(), "(case-sensitive)")
+
+	// Regular expressions are matched on demand
+	m = update(m, key("ctrl+t"))
+	m = update(m, key("esc"))
+	for _, k := range []string{"/", "ctrl+r", "^", "f", "o", "+"} {
+		m = update(m, key(k))
+	}
+	assert.Len(t, m.matches, 2)
+	m = update(m, key("("))
+	assert.Empty(t, m.matches)
+	assert.Error(t, m.findErr)
+	assert.Contains(t, m.fileContentView(), "missing closing )")
+}
+
+func TestMarkMatches(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	m := Model{matches: []fileMatch{{line: 1, start: 1, end: 3}, {line: 1, start: 4, end: 5}}, matchIndex: 1}
+	line := "\x1b[31mabcd\x1b[0m e"
+	assert.Equal(t, "abcd e", plainText(line))
+	assert.Equal(t, line, m.markLine(0, line, plainText(line)))
+	assert.Equal(t,
+		"\x1b[31ma"+config.SearchMatchStyle.Render("bc")+"\x1b[31md\x1b[0m"+config.CurrentMatchStyle.Render(" ")+"e",
+		m.markLine(1, line, plainText(line)))
+}
+
+func TestSelection(t *testing.T) {
+	fake := newTestProvider()
+	content := "héllo wörld\n日本語\n" + strings.Repeat("x\n", 30)
+	fake.AddFile("octocat", "Hello-World", "PICK.txt", content)
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "PICK.txt", m.fileContents[0].Name)
+	m = update(m, key("enter"))
+
+	// Characters are selected rune by rune
+	for _, k := range []string{"v", "l", "l", "l"} {
+		m = update(m, key(k))
+	}
+	assert.True(t, m.selectMode)
+	assert.Equal(t, "héll", m.selectedText())
+	m = update(m, key("right"))
+	assert.Equal(t, "héllo", m.selectedText())
+	m = update(m, key("left"))
+	assert.Equal(t, "héll", m.selectedText())
+	assert.Contains(t, m.fileContentView(), "selecting, cursor at 1:4")
+
+	// The cursor stays within shorter lines
+	m = update(m, key("j"))
+	assert.Equal(t, textPosition{line: 1, column: 2}, m.selectCursor)
+	assert.Equal(t, "héllo wörld\n日本語", m.selectedText())
+
+	// Whole lines are selected with V, which stops selecting them when pressed again
+	m = update(m, key("V"))
+	assert.Equal(t, "héllo wörld\n日本語\n", m.selectedText())
+	m = update(m, key("V"))
+	assert.False(t, m.selectMode)
+	assert.Equal(t, "fileContent", m.currentView)
+
+	m = update(m, key("ctrl+a"))
+	assert.Equal(t, content, m.selectedText())
+	m = update(m, key("esc"))
+	assert.False(t, m.selectMode)
+
+	// The viewport scrolls with the cursor, the selection shown within it
+	m = update(m, key("v"))
+	m = update(m, key("pgdown"))
+	assert.Equal(t, 20, m.selectCursor.line)
+	assert.Greater(t, m.topLine(), 1)
+	view := m.fileContentView()
+	assert.Contains(t, view, "21 │ x")
+	assert.Contains(t, view, "Press Esc to deselect")
+
+	m = update(m, key("esc"))
+	assert.False(t, m.selectMode)
+	assert.Equal(t, "fileContent", m.currentView)
+}
+
+func TestReadmeWidths(t *testing.T) {
+	fake := newTestProvider()
+	readme := "# Hi there\n\n" + strings.Repeat("word ", 60) + "\n"
+	fake.AddFile("octocat", "octocat", "README.md", readme)
+	fake.AddFile("octocat", "Hello-World", "README.md", readme)
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, tea.WindowSizeMsg{Width: 80, Height: 40})
+	m = update(m, key("tab"))
+	m = update(m, key("tab"))
+	assert.Equal(t, "profile", m.currentView)
+	assertFits(t, m.View(), 80)
+
+	// The files view footer is wider than 80 columns with or without a README
+	m = update(m, tea.WindowSizeMsg{Width: 140, Height: 40})
+	m = update(m, key("tab"))
+	m = update(m, key("enter"))
+	assert.NotEmpty(t, m.repoReadmeText)
+	assertFits(t, m.View(), 140)
+}
+
+// assertFits checks that none of the lines of view is wider than width
+func assertFits(t *testing.T, view string, width int) {
+	t.Helper()
+	for i, line := range strings.Split(view, "\n") {
+		assert.LessOrEqual(t, ansi.StringWidth(line), width, "line %d: %q", i+1, line)
+	}
+}
+
+func TestSelectionOfRenderedMarkdown(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddFile("octocat", "Hello-World", "NOTES.md", "# Notes\n\nSee the [docs](https://example.com/docs).\n")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "NOTES.md", m.selected["file"])
+
+	// Showing the shorter source ends the selection of the rendered lines
+	m = update(m, key("v"))
+	m = update(m, key("pgdown"))
+	assert.Greater(t, m.selectCursor.line, 4)
+	m = update(m, key("m"))
+	assert.False(t, m.selectMode)
+	m = update(m, key("$"))
+
+	// So does rendering the document again
+	m = update(m, key("m"))
+	m = update(m, key("v"))
+	m = update(m, tea.WindowSizeMsg{Width: 60, Height: 30})
+	assert.False(t, m.selectMode)
+
+	// A cursor past the lines shown is brought back to them
+	m = update(m, key("v"))
+	m.selectCursor.line = 100
+	m = update(m, key("$"))
+	assert.Equal(t, len(m.lineStarts)-1, m.selectCursor.line)
+}
+
+func TestTruncatedText(t *testing.T) {
+	// A rune cut at the cap is dropped rather than turning the text into Latin-1
+	file := &forge.File{Truncated: true, Content: []byte("héllo wörld ✓"[:len("héllo wörld ✓")-1])}
+	assert.Equal(t, "héllo wörld ", fileText(file))
+	file.Content = []byte("héllo wörld é"[:len("héllo wörld é")-1])
+	assert.Equal(t, "héllo wörld ", fileText(file))
+	file.Content = []byte("héllo")
+	assert.Equal(t, "héllo", fileText(file))
+	file.Content = []byte("caf\xe9 cr\xe8me")
+	assert.Equal(t, "café crème", fileText(file))
+}
+
+func TestLatin1File(t *testing.T) {
+	fake := newTestProvider()
+	fake.AddFile("octocat", "Hello-World", "LATIN.txt", "caf\xe9 cr\xe8me\n")
+	m := update(InitialModel(fake, "octocat"), key("enter"))
+	m = update(m, key("enter"))
+	assert.Equal(t, "LATIN.txt", m.fileContents[0].Name)
+	m = update(m, key("enter"))
+	assert.False(t, m.file.Binary)
+	assert.Equal(t, "café crème\n", m.fileContent)
+}
@@ -0,0 +1,212 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ghexplorer/forge"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxLinkHops bounds how many chained symlinks are followed
+const maxLinkHops = 8
+
+// location identifies a directory of a repository at a ref, the empty ref being
+// the default branch. Paths start with a slash, the root being the empty path.
+type location struct {
+	owner, repo, ref, path string
+}
+
+// linkedFileMsg carries a file reached through a symlink and the listing of its directory
+type linkedFileMsg struct {
+	contents contentsMsg
+	name     string
+	file     fileContentMsg
+}
+
+// location returns the directory shown in the files view
+func (m Model) location() location {
+	return location{owner: m.selected["owner"], repo: m.selected["repository"], ref: m.selected["ref"], path: m.selected["path"]}
+}
+
+// showContents displays a fetched directory listing
+func (m Model) showContents(msg contentsMsg) Model {
+	m.selected["owner"] = msg.owner
+	m.selected["repository"] = msg.repo
+	m.selected["ref"] = msg.ref
+	m.selected["path"] = msg.path
+	if msg.parent != nil {
+		m.parents = append(m.parents, *msg.parent)
+	}
+	m.listedFiles = msg.contents
+	m.repoReadme = msg.readme
+	m.repoReadmeText = renderReadme(m.repoReadme, m.readme.Width)
+	if msg.readmeErr != nil {
+		m.banner = fmt.Errorf("could not fetch the README: %w", msg.readmeErr)
+	}
+	m.filters["files"] = ""
+	m = m.filterFiles()
+	m.staleAt["files"] = msg.staleAt
+	m.currentView = "files"
+	m.cursor = 0
+	return m
+}
+
+// showFile displays fetched file content
+func (m Model) showFile(msg fileContentMsg) Model {
+	m.file = msg.file
+	m.fileContent = fileText(msg.file)
+	m = m.highlightFile()
+	m = m.renderMarkdown()
+	m.showSource = false
+	m.staleAt["fileContent"] = msg.staleAt
+	m.xOffset = 0
+	m.selectMode = false
+	m.findQuery = ""
+	m.matches = nil
+	if m.currentView == "fileContent" {
+		m = m.layoutContent()
+		m.viewport.GotoTop()
+	}
+	return m
+}
+
+// open enters a directory entry: directories are listed, files shown, symlinks
+// followed and submodules opened in their own repository
+func (m Model) open(entry *forge.FileInfo) (tea.Model, tea.Cmd) {
+	switch entry.Type {
+	case "file":
+		m.selected["file"] = entry.Name
+		m.currentView = "fileContent"
+		cmd := m.request(m.fetchFileContent)
+		return m, cmd
+	case "dir":
+		loc := m.location()
+		loc.path += "/" + entry.Name
+		cmd := m.request(m.fetchContents(loc))
+		return m, cmd
+	case "symlink":
+		loc := m.location()
+		cmd := m.request(func(ctx context.Context) tea.Msg {
+			entry, err := m.describe(ctx, loc, entry)
+			if err != nil {
+				return err
+			}
+			if entry.Target == "" {
+				return fmt.Errorf("the target of symlink %s is unknown", entry.Name)
+			}
+			return m.followLink(loc, resolveLink(loc.path, entry.Target))(ctx)
+		})
+		return m, cmd
+	case "submodule":
+		parent := m.location()
+		cmd := m.request(func(ctx context.Context) tea.Msg {
+			entry, err := m.describe(ctx, parent, entry)
+			if err != nil {
+				return err
+			}
+			if entry.SubmoduleGitURL == "" {
+				return fmt.Errorf("the URL of submodule %s is unknown", entry.Name)
+			}
+			owner, repo, err := forge.SubmoduleRepository(entry.SubmoduleGitURL, parent.owner, parent.repo)
+			if err != nil {
+				return err
+			}
+			// The submodule is shown at the commit the parent records
+			msg := m.fetchContents(location{owner: owner, repo: repo, ref: entry.SHA})(ctx)
+			if contents, ok := msg.(contentsMsg); ok {
+				contents.parent = &parent
+				return contents
+			}
+			return msg
+		})
+		return m, cmd
+	default:
+		m.banner = fmt.Errorf("cannot open %s of type %s", entry.Name, entry.Type)
+		return m, nil
+	}
+}
+
+// describe completes a symlink or submodule entry listed in loc with the target
+// or URL its listing omits, when the provider can
+func (m Model) describe(ctx context.Context, loc location, entry *forge.FileInfo) (*forge.FileInfo, error) {
+	if entry.Target != "" || entry.SubmoduleGitURL != "" {
+		return entry, nil
+	}
+	provider, _ := m.cacheTraced()
+	describer, ok := provider.(forge.EntryDescriber)
+	if !ok {
+		return entry, nil
+	}
+	return describer.DescribeEntry(ctx, loc.owner, loc.repo, loc.ref, entry)
+}
+
+// resolveLink returns the path a symlink in dir points to. Targets leaving the
+// repository are clamped to its root.
+func resolveLink(dir, target string) string {
+	if !strings.HasPrefix(target, "/") {
+		target = dir + "/" + target
+	}
+	target = path.Clean("/" + target)
+	if target == "/" {
+		return ""
+	}
+	return target
+}
+
+// followLink opens the file or directory at target, following chained symlinks
+func (m Model) followLink(loc location, target string) func(ctx context.Context) tea.Msg {
+	return func(ctx context.Context) tea.Msg {
+		provider, trace := m.cacheTraced()
+		for hops := 0; hops < maxLinkHops; hops++ {
+			if target == "" {
+				return m.fetchContents(location{owner: loc.owner, repo: loc.repo, ref: loc.ref})(ctx)
+			}
+			dir, name := path.Split(target)
+			dir = strings.TrimSuffix(dir, "/")
+			entries, err := provider.ListContents(ctx, loc.owner, loc.repo, loc.ref, dir)
+			if err != nil {
+				return err
+			}
+
+			var entry *forge.FileInfo
+			for _, e := range entries {
+				if e.Name == name {
+					entry = e
+				}
+			}
+			switch {
+			case entry == nil:
+				return fmt.Errorf("symlink target %s does not exist", target)
+			case entry.Type == "dir":
+				return m.fetchContents(location{owner: loc.owner, repo: loc.repo, ref: loc.ref, path: target})(ctx)
+			case entry.Type == "file":
+				file, err := provider.GetFile(ctx, loc.owner, loc.repo, loc.ref, target)
+				if err != nil {
+					return err
+				}
+				staleAt, _ := trace.Oldest()
+				return linkedFileMsg{
+					contents: contentsMsg{location: location{owner: loc.owner, repo: loc.repo, ref: loc.ref, path: dir}, contents: entries, staleAt: staleAt},
+					name:     name,
+					file:     fileContentMsg{file: file, staleAt: staleAt},
+				}
+			case entry.Type == "symlink":
+				entry, err := m.describe(ctx, location{owner: loc.owner, repo: loc.repo, ref: loc.ref, path: dir}, entry)
+				if err != nil {
+					return err
+				}
+				if entry.Target == "" {
+					return fmt.Errorf("cannot follow symlink to %s", target)
+				}
+				target = resolveLink(dir, entry.Target)
+			default:
+				return fmt.Errorf("cannot follow symlink to %s", target)
+			}
+		}
+		return errors.New("too many levels of symbolic links")
+	}
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"ghexplorer/markdown"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// renderReadme renders a README to width columns, Markdown rendered and other
+// formats as text
+func renderReadme(file *forge.File, width int) string {
+	switch {
+	case file == nil || file.Binary:
+		return ""
+	case markdown.IsMarkdown(file.Path):
+		return markdown.Render(file.Content, width)
+	default:
+		return ansi.Wrap(strings.TrimRight(string(file.Content), "\n"), width, "")
+	}
+}
+
+// resizeReadmes fits the profile README to the terminal and renders both
+// READMEs again to its width, inside the border and padding of ReadmeStyle
+func (m Model) resizeReadmes(width, height int) Model {
+	width = max(1, width-config.ReadmeStyle.GetHorizontalFrameSize())
+	m.readme = viewport.New(width, max(config.MinReadmeHeight, height-config.ProfileHeight))
+	m.readme.SetContent(renderReadme(m.profileReadme, width))
+	m.repoReadmeText = renderReadme(m.repoReadme, width)
+	return m
+}
+
+// profileReadmeView renders the scrollable profile README of the Overview tab
+func (m Model) profileReadmeView() string {
+	if m.profileReadme == nil {
+		return ""
+	}
+	return "\n" + config.ReadmeStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		config.HeaderStyle.Render(path.Base(m.profileReadme.Path)),
+		m.readme.View(),
+	))
+}
+
+// repoReadmeView renders the first lines of the README of the repository root
+// below its listing
+func (m Model) repoReadmeView() string {
+	if m.repoReadmeText == "" {
+		return ""
+	}
+	lines := strings.Split(m.repoReadmeText, "\n")
+	name := path.Base(m.repoReadme.Path)
+	if len(lines) > config.ReadmePreviewLines {
+		lines = append(lines[:config.ReadmePreviewLines], config.FooterStyle.Render("… open "+name+" to read on"))
+	}
+	return "\n" + config.ReadmeStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		config.HeaderStyle.Render(name),
+		strings.Join(lines, "\n"),
+	))
+}
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"ghexplorer/fuzzy"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// refEntry is a branch or tag offered by the ref picker
+type refEntry struct {
+	kind string
+	ref  *forge.Ref
+}
+
+// refsMsg carries the branches and tags of a repository
+type refsMsg struct {
+	refs []refEntry
+}
+
+// openRefs shows the ref picker of the repository browsed
+func (m Model) openRefs() (tea.Model, tea.Cmd) {
+	m.currentView = "refs"
+	m.refs = nil
+	m.refQuery = ""
+	m.cursor = 0
+	cmd := m.request(m.fetchRefs(m.location()))
+	return m, cmd
+}
+
+// fetchRefs handles the branches and tags fetching of the repository at loc
+func (m Model) fetchRefs(loc location) func(ctx context.Context) tea.Msg {
+	return func(ctx context.Context) tea.Msg {
+		branches, err := m.provider.ListBranches(ctx, loc.owner, loc.repo)
+		if err != nil {
+			return err
+		}
+		tags, err := m.provider.ListTags(ctx, loc.owner, loc.repo)
+		if err != nil {
+			return err
+		}
+
+		refs := make([]refEntry, 0, len(branches)+len(tags))
+		for _, branch := range branches {
+			refs = append(refs, refEntry{kind: "branch", ref: branch})
+		}
+		for _, tag := range tags {
+			refs = append(refs, refEntry{kind: "tag", ref: tag})
+		}
+		return refsMsg{refs: refs}
+	}
+}
+
+// updateRefs edits the ref typed in the picker and picks the ref chosen. It
+// reports false for the keys handled as usual, which move through the refs
+// and go back.
+func (m Model) updateRefs(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		model, cmd := m.pickRef()
+		return model, cmd, true
+	case tea.KeyBackspace:
+		runes := []rune(m.refQuery)
+		if len(runes) > 0 {
+			m.refQuery = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.refQuery += string(msg.Runes)
+	default:
+		return m, nil, false
+	}
+	m.cursor = 0
+	return m, nil, true
+}
+
+// refMatches returns the refs matching the typed ref, best first
+func (m Model) refMatches() []refEntry {
+	names := make([]string, len(m.refs))
+	for i, entry := range m.refs {
+		names[i] = entry.ref.Name
+	}
+	var matches []refEntry
+	for _, ranked := range fuzzy.Rank(m.refQuery, names) {
+		matches = append(matches, m.refs[ranked.Index])
+	}
+	return matches
+}
+
+// pickRef lists the root of the repository at the selected ref or, when no ref
+// matches, at the revision typed, such as a commit SHA
+func (m Model) pickRef() (tea.Model, tea.Cmd) {
+	ref := strings.TrimSpace(m.refQuery)
+	if matches := m.refMatches(); m.cursor < len(matches) {
+		ref = matches[m.cursor].ref.Name
+	}
+	if ref == "" {
+		return m, nil
+	}
+	loc := m.location()
+	loc.ref, loc.path = ref, ""
+	cmd := m.request(m.fetchContents(loc))
+	return m, cmd
+}
+
+// refName names ref for display, the empty ref being the default branch
+func refName(ref string) string {
+	if ref == "" {
+		return "default branch"
+	}
+	return ref
+}
+
+// refsView handles the CLI ref picker view
+func (m Model) refsView() string {
+	var content strings.Builder
+
+	content.WriteString(m.bannerView())
+	content.WriteString(config.HeaderStyle.Render(fmt.Sprintf("Refs of %s", m.selected["repository"])))
+	content.WriteString("\n\n")
+	content.WriteString(config.DetailStyle.Render("Ref: ") + config.ValueStyle.Render(m.refQuery+"▏"))
+	content.WriteString("\n\n")
+
+	matches := m.refMatches()
+	switch {
+	case m.refs == nil:
+		content.WriteString(m.spinner.View() + " Loading branches and tags...")
+	case len(matches) == 0:
+		content.WriteString(config.ValueStyle.Render("No branch or tag matches; press Enter to browse this revision"))
+	}
+
+	start := m.cursor / config.ItemsPerPage * config.ItemsPerPage
+	for i := start; i < min(start+config.ItemsPerPage, len(matches)); i++ {
+		entry := matches[i]
+		line := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			config.DetailStyle.Render(fmt.Sprintf("%-7s", entry.kind)),
+			config.RepositoryStyle.Render(entry.ref.Name),
+			config.DetailStyle.Render("  "+entry.ref.SHA[:min(len(entry.ref.SHA), 7)]),
+		)
+		if i == m.cursor {
+			content.WriteString("> " + config.SelectedStyle.Render(line) + "\n")
+		} else {
+			content.WriteString("  " + line + "\n")
+		}
+	}
+
+	content.WriteString(config.FooterStyle.Render("\nType to filter or enter a commit SHA • Enter to browse the ref • Esc to go back"))
+	return config.DocStyle.Render(content.String())
+}
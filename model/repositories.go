@@ -0,0 +1,88 @@
+package model
+
+import (
+	"ghexplorer/forge"
+	"sort"
+	"strings"
+)
+
+// arrange applies the sort or filter control bound to key in the repositories view
+func (m Model) arrange(key string) Model {
+	switch key {
+	case "s":
+		m.repoSort = cycle(forge.RepositorySorts, m.repoSort)
+	case "l":
+		m.repoFilter.Language = cycle(m.listedValues(func(r *forge.Repository) []string { return []string{r.Language} }), m.repoFilter.Language)
+	case "t":
+		m.repoFilter.Topic = cycle(m.listedValues(func(r *forge.Repository) []string { return r.Topics }), m.repoFilter.Topic)
+	case "f":
+		m.repoFilter.HideForks = !m.repoFilter.HideForks
+	case "a":
+		m.repoFilter.HideArchived = !m.repoFilter.HideArchived
+	case "c":
+		m.repoSort = ""
+		m.repoFilter = forge.RepositoryFilter{}
+	default:
+		return m
+	}
+	m = m.arrangeRepositories()
+	m.cursor = 0
+	return m
+}
+
+// arrangeRepositories shows the listed repositories passing the filter in the
+// chosen order, ranked by the fuzzy filter when one is typed
+func (m Model) arrangeRepositories() Model {
+	m.repositories = m.repoFilter.Apply(m.listed)
+	// The sort keys offered are all known
+	_ = forge.SortRepositories(m.repositories, m.repoSort)
+	if query := m.filters["repositories"]; query != "" {
+		m.repositories = rankRepositories(m.repositories, query)
+	}
+	return m
+}
+
+// listedValues returns the distinct non-empty values of the listed repositories, sorted
+func (m Model) listedValues(values func(*forge.Repository) []string) []string {
+	seen := make(map[string]bool)
+	var distinct []string
+	for _, repo := range m.listed {
+		for _, v := range values(repo) {
+			if v != "" && !seen[strings.ToLower(v)] {
+				seen[strings.ToLower(v)] = true
+				distinct = append(distinct, v)
+			}
+		}
+	}
+	sort.Slice(distinct, func(i, j int) bool { return strings.ToLower(distinct[i]) < strings.ToLower(distinct[j]) })
+	return distinct
+}
+
+// cycle returns the value following current in values, going back to none
+// after the last one
+func cycle(values []string, current string) string {
+	if current == "" {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	for i, v := range values {
+		if strings.EqualFold(v, current) && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return ""
+}
+
+// arrangement describes the sort and filters applied to the repositories view
+func (m Model) arrangement() string {
+	var parts []string
+	if m.repoSort != "" {
+		parts = append(parts, "sorted by "+m.repoSort)
+	}
+	if filter := m.repoFilter.String(); filter != "" {
+		parts = append(parts, filter)
+	}
+	return strings.Join(parts, " • ")
+}
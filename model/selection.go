@@ -0,0 +1,222 @@
+package model
+
+import (
+	"fmt"
+	"ghexplorer/config"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// textPosition is a character of the open file as shown, the column counting
+// the runes before it in the line numbered line+1
+type textPosition struct {
+	line, column int
+}
+
+// before reports whether p comes before q in the file
+func (p textPosition) before(q textPosition) bool {
+	return p.line < q.line || p.line == q.line && p.column < q.column
+}
+
+// startSelection selects from the start of the line at the top of the
+// viewport, whole lines when lines is set
+func (m Model) startSelection(lines bool) Model {
+	if m.file == nil || m.file.Binary || len(m.lineStarts) == 0 {
+		return m
+	}
+	m.selectMode = true
+	m.selectLines = lines
+	m.selectAnchor = textPosition{line: max(0, m.topLine()-1)}
+	m.selectCursor = m.selectAnchor
+	m.selectColumn = 0
+	return m.layoutContent()
+}
+
+// selectAll selects every line of the open file
+func (m Model) selectAll() Model {
+	if m.file == nil || m.file.Binary || len(m.lineStarts) == 0 {
+		return m
+	}
+	m.selectMode = true
+	m.selectLines = true
+	m.selectAnchor = textPosition{}
+	m.selectCursor = textPosition{line: len(m.lineStarts) - 1}
+	m.selectColumn = 0
+	return m.layoutContent()
+}
+
+// stopSelection leaves the selection mode, unmarking the selection
+func (m Model) stopSelection() Model {
+	m.selectMode = false
+	if m.currentView != "fileContent" {
+		return m
+	}
+	return m.layoutContent()
+}
+
+// updateSelection moves the cursor of the selection and copies the text
+// selected. It reports false for the keys handled as usual.
+func (m Model) updateSelection(msg tea.KeyMsg) (Model, bool) {
+	text := m.shownText()
+	cursor := m.selectCursor
+	cursor.line = max(0, min(cursor.line, len(text)-1))
+	// Moving up and down, the cursor goes back to the column it was moved to
+	// when the lines are long enough
+	vertical := false
+	switch msg.String() {
+	case "left", "h":
+		cursor.column--
+	case "right", "l":
+		cursor.column++
+	case "up", "k":
+		cursor.line--
+		vertical = true
+	case "down", "j":
+		cursor.line++
+		vertical = true
+	case "pgup":
+		cursor.line -= m.viewport.Height
+		vertical = true
+	case "pgdown":
+		cursor.line += m.viewport.Height
+		vertical = true
+	case "home", "0":
+		cursor.column = 0
+	case "end", "$":
+		cursor.column = utf8.RuneCountInString(text[cursor.line])
+	case "v", "V":
+		if m.selectLines == (msg.String() == "V") {
+			return m.stopSelection(), true
+		}
+		m.selectLines = msg.String() == "V"
+		return m.layoutContent(), true
+	case "y", "ctrl+c":
+		if err := clipboard.WriteAll(m.selectedText()); err != nil {
+			m.banner = fmt.Errorf("could not copy the selection: %w", err)
+		}
+		return m.stopSelection(), true
+	case "esc", "ctrl+d":
+		return m.stopSelection(), true
+	default:
+		return m, false
+	}
+
+	if vertical {
+		cursor.column = m.selectColumn
+	}
+	cursor.line = max(0, min(cursor.line, len(text)-1))
+	line := text[cursor.line]
+	cursor.column = max(0, min(cursor.column, utf8.RuneCountInString(line)-1))
+	m.selectCursor = cursor
+	if !vertical {
+		m.selectColumn = cursor.column
+	}
+	start := runeOffset(line, cursor.column)
+	end := runeOffset(line, cursor.column+1)
+	return m.reveal(cursor.line, start, end), true
+}
+
+// selection returns the ends of the selection in order
+func (m Model) selection() (start, end textPosition) {
+	if m.selectCursor.before(m.selectAnchor) {
+		return m.selectCursor, m.selectAnchor
+	}
+	return m.selectAnchor, m.selectCursor
+}
+
+// selectedRange returns the bytes selected in text, the line numbered i+1, and
+// whether its line break is selected too. It reports false for the lines out
+// of the selection.
+func (m Model) selectedRange(i int, text string) (start, end int, lineBreak, ok bool) {
+	first, last := m.selection()
+	if !m.selectMode || i < first.line || i > last.line {
+		return 0, 0, false, false
+	}
+	if m.selectLines {
+		return 0, len(text), i < len(m.lineStarts)-1, true
+	}
+	start, end = 0, len(text)
+	if i == first.line {
+		start = runeOffset(text, first.column)
+	}
+	if i == last.line {
+		end = runeOffset(text, last.column+1)
+	}
+	return start, end, i < last.line, true
+}
+
+// selectedText returns the text selected, whole lines with their line break
+func (m Model) selectedText() string {
+	var b strings.Builder
+	for i, text := range m.shownText() {
+		start, end, lineBreak, ok := m.selectedRange(i, text)
+		if !ok {
+			continue
+		}
+		b.WriteString(text[start:end])
+		if lineBreak {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// markLine renders the selection and the matches of the search in the line of
+// the open file numbered i+1, whose text is text. The line break of the lines
+// selected through their end is shown as a selected space.
+func (m Model) markLine(i int, line, text string) string {
+	ranges := m.matchRanges(i)
+	start, end, lineBreak, ok := m.selectedRange(i, text)
+	if !ok {
+		return markRanges(line, ranges)
+	}
+
+	// The selection hides the matches it covers
+	var marked []textRange
+	for _, r := range ranges {
+		if r.start < start {
+			marked = append(marked, textRange{start: r.start, end: min(r.end, start), style: r.style})
+		}
+		if r.end > end {
+			marked = append(marked, textRange{start: max(r.start, end), end: r.end, style: r.style})
+		}
+	}
+	if end > start {
+		marked = append(marked, textRange{start: start, end: end, style: config.SelectedStyle})
+	}
+	sort.Slice(marked, func(a, b int) bool { return marked[a].start < marked[b].start })
+
+	line = markRanges(line, marked)
+	if lineBreak {
+		line += config.SelectedStyle.Render(" ")
+	}
+	return line
+}
+
+// selectionView shows where the cursor of the selection is
+func (m Model) selectionView() string {
+	if !m.selectMode {
+		return ""
+	}
+	mode := "selecting"
+	if m.selectLines {
+		mode = "selecting lines"
+	}
+	return config.DetailStyle.Render(fmt.Sprintf(" • %s, cursor at %d:%d", mode, m.selectCursor.line+1, m.selectCursor.column+1))
+}
+
+// runeOffset returns the byte offset of the rune numbered n+1 of text, or the
+// length of text when it is shorter
+func runeOffset(text string, n int) int {
+	for offset := range text {
+		if n == 0 {
+			return offset
+		}
+		n--
+	}
+	return len(text)
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"ghexplorer/config"
+	"ghexplorer/syntax"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// highlightFile highlights the open file and keeps its lines without colors
+func (m Model) highlightFile() Model {
+	m.highlighted = m.highlightLines()
+	text := m.fileContent
+	if m.highlighted != nil {
+		text = strings.Join(m.highlighted, "\n")
+	}
+	m.plainLines = plainLines(text)
+	return m
+}
+
+// highlightLines returns the lines of the open file highlighted, reusing those
+// highlighted when it was last shown. Binary, large and plain text files are
+// not highlighted.
+func (m Model) highlightLines() []string {
+	if m.file == nil || m.file.Binary || len(m.file.Content) > config.MaxHighlightSize {
+		return nil
+	}
+
+	key := m.selected["owner"] + "/" + m.selected["repository"] + "@" + m.selected["ref"] + ":" + m.selected["path"] + "/" + m.selected["file"] + "#" + m.file.SHA
+	if lines, ok := m.highlights[key]; ok {
+		return lines
+	}
+	lines, ok := syntax.Highlight(m.selected["file"], m.fileContent, config.SyntaxTheme, lipgloss.ColorProfile())
+	if !ok {
+		return nil
+	}
+	if len(m.highlights) >= config.HighlightCacheSize {
+		clear(m.highlights)
+	}
+	m.highlights[key] = lines
+	return lines
+}
+
+// renderedContent returns the open file as shown: Markdown documents rendered
+// unless their source is shown, and other files highlighted when their language
+// is known
+func (m Model) renderedContent() string {
+	if m.markdown != "" && !m.showSource {
+		return m.markdown
+	}
+	if m.highlighted == nil {
+		return m.fileContent
+	}
+	return strings.Join(m.highlighted, "\n")
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"ghexplorer/config"
+	"ghexplorer/forge"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// treeMsg carries the whole tree of a repository
+type treeMsg struct {
+	location
+	tree *forge.Tree
+}
+
+// treeRow is an entry shown in the tree view, indented by its depth
+type treeRow struct {
+	entry *forge.FileInfo
+	depth int
+}
+
+// openTree shows the tree of the repository browsed
+func (m Model) openTree() (tea.Model, tea.Cmd) {
+	m.currentView = "tree"
+	m.cursor = 0
+	return m.loadTree()
+}
+
+// loadTree fetches the tree of the repository browsed unless it is the tree
+// loaded last
+func (m Model) loadTree() (tea.Model, tea.Cmd) {
+	loc := m.location()
+	loc.path = ""
+	if m.tree != nil && m.treeAt == loc {
+		return m, nil
+	}
+	m.tree = nil
+	m.treeDirs = nil
+	m.finderMatches = nil
+	m.expanded = make(map[string]bool)
+	cmd := m.request(m.fetchTree(loc))
+	return m, cmd
+}
+
+// fetchTree handles the recursive tree fetching of a repository
+func (m Model) fetchTree(loc location) func(ctx context.Context) tea.Msg {
+	return func(ctx context.Context) tea.Msg {
+		tree, err := m.provider.GetTree(ctx, loc.owner, loc.repo, loc.ref)
+		if err != nil {
+			return err
+		}
+		return treeMsg{location: loc, tree: tree}
+	}
+}
+
+// showTree displays a fetched tree, its directories collapsed
+func (m Model) showTree(msg treeMsg) Model {
+	m.tree = msg.tree
+	m.treeAt = msg.location
+	m.treeDirs = msg.tree.ByDir()
+	if m.currentView == "finder" {
+		m = m.rankFinder()
+	}
+	return m
+}
+
+// treeRows lists the entries of the expanded directories in depth-first order
+func (m Model) treeRows() []treeRow {
+	var rows []treeRow
+	var walk func(dir string, depth int)
+	walk = func(dir string, depth int) {
+		for _, entry := range m.treeDirs[dir] {
+			rows = append(rows, treeRow{entry: entry, depth: depth})
+			if entry.Type == "dir" && m.expanded[entry.Path] {
+				walk(entry.Path, depth+1)
+			}
+		}
+	}
+	walk("", 0)
+	return rows
+}
+
+// openTreeEntry expands or collapses a directory of the tree. Files are opened
+// in the files view of their directory, and submodules shown there.
+func (m Model) openTreeEntry(entry *forge.FileInfo) (tea.Model, tea.Cmd) {
+	switch entry.Type {
+	case "dir":
+		m.expanded[entry.Path] = !m.expanded[entry.Path]
+		return m, nil
+	case "submodule":
+		loc := m.treeAt
+		if dir := path.Dir(entry.Path); dir != "." {
+			loc.path = "/" + dir
+		}
+		cmd := m.request(m.fetchContents(loc))
+		return m, cmd
+	default:
+		cmd := m.request(m.followLink(m.treeAt, "/"+entry.Path))
+		return m, cmd
+	}
+}
+
+// treeView handles the CLI tree view
+func (m Model) treeView() string {
+	var content strings.Builder
+
+	content.WriteString(m.bannerView())
+	header := lipgloss.JoinVertical(
+		lipgloss.Left,
+		config.HeaderStyle.Render(fmt.Sprintf("Tree: %s", m.treeAt.repo)),
+		config.ValueStyle.Render(fmt.Sprintf("Ref: %s", refName(m.treeAt.ref))),
+	)
+	content.WriteString(config.CardStyle.Render(header))
+	content.WriteString("\n\n")
+
+	if m.tree == nil {
+		content.WriteString(m.spinner.View() + " Loading tree...")
+		return config.DocStyle.Render(content.String())
+	}
+	if m.tree.Truncated {
+		content.WriteString(config.StaleStyle.Render("The tree is too large to be listed entirely; some entries are missing"))
+		content.WriteString("\n\n")
+	}
+
+	rows := m.treeRows()
+	currentPage, totalPages, startIdx, endIdx := m.getPaginationInfo()
+	for i := startIdx; i < endIdx; i++ {
+		row := rows[i]
+		style, icon, details := entryStyle(row.entry)
+		marker := "  "
+		if row.entry.Type == "dir" {
+			marker = "▸ "
+			if m.expanded[row.entry.Path] {
+				marker = "▾ "
+			}
+		}
+
+		line := strings.Repeat("  ", row.depth) + marker + icon + " " + style.Render(row.entry.Name) + config.DetailStyle.Render(details)
+		if i == m.cursor {
+			content.WriteString("> " + config.SelectedStyle.Render(line) + "\n")
+		} else {
+			content.WriteString("  " + line + "\n")
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(renderPagination(currentPage, totalPages))
+	content.WriteString(config.FooterStyle.Render("\nPress Enter to expand folders or open files • t or Esc to go back to the files") + m.rateLimitStatus())
+	return config.DocStyle.Render(content.String())
+}
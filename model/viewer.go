@@ -0,0 +1,315 @@
+package model
+
+import (
+	"fmt"
+	"ghexplorer/config"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// layoutContent lays the open file out in the viewport, each line after its
+// number in the gutter and with the selection and the matches of the search
+// marked. Lines wrap at the viewport width unless noWrap is set, in which case
+// they are shown from column xOffset. The line at the top of the viewport
+// stays there.
+func (m Model) layoutContent() Model {
+	top := m.topLine()
+	lines := strings.Split(m.renderedContent(), "\n")
+	text := m.shownText()
+	gutter := 0
+	if m.showsLineNumbers() {
+		gutter = len(strconv.Itoa(len(lines)))
+	}
+	// The gutter is followed by " │ "
+	width := max(1, m.viewport.Width-gutter-3)
+	if gutter == 0 {
+		width = max(1, m.viewport.Width)
+	}
+
+	rows := make([]string, 0, len(lines))
+	m.lineStarts = make([]int, len(lines))
+	lineWidth := 0
+	for i, line := range lines {
+		m.lineStarts[i] = len(rows)
+		// Nothing is shown yet while the first file loads
+		plain := ""
+		if i < len(text) {
+			plain = text[i]
+		}
+		line = expandTabs(m.markLine(i, line, plain))
+		lineWidth = max(lineWidth, ansi.StringWidth(line))
+		wrapped := []string{ansi.Truncate(skipColumns(line, m.xOffset), width, "")}
+		if !m.noWrap {
+			wrapped = strings.Split(ansi.Hardwrap(line, width, true), "\n")
+		}
+		for j, row := range wrapped {
+			if gutter > 0 {
+				number := ""
+				if j == 0 {
+					number = strconv.Itoa(i + 1)
+				}
+				row = config.GutterStyle.Render(fmt.Sprintf("%*s │ ", gutter, number)) + row
+			}
+			rows = append(rows, row)
+		}
+	}
+	m.textWidth = width
+	m.maxXOffset = max(0, lineWidth-width)
+	m.xOffset = min(m.xOffset, m.maxXOffset)
+
+	m.viewport.SetContent(strings.Join(rows, "\n"))
+	return m.scrollToLine(top)
+}
+
+// shownText returns the text of the lines of the open file as shown, without
+// their colors
+func (m Model) shownText() []string {
+	if m.markdown != "" && !m.showSource {
+		return m.markdownLines
+	}
+	return m.plainLines
+}
+
+// showsLineNumbers reports whether the open file is shown with line numbers,
+// which rendered Markdown documents and binary files are not
+func (m Model) showsLineNumbers() bool {
+	if m.file != nil && m.file.Binary {
+		return false
+	}
+	return m.markdown == "" || m.showSource
+}
+
+// topLine returns the number of the line at the top of the viewport, counted
+// from 1, or 0 when no file is laid out
+func (m Model) topLine() int {
+	return sort.Search(len(m.lineStarts), func(i int) bool {
+		return m.lineStarts[i] > m.viewport.YOffset
+	})
+}
+
+// scrollToLine scrolls the viewport to show the line numbered n at the top,
+// or the first or last line when there is no such line
+func (m Model) scrollToLine(n int) Model {
+	if len(m.lineStarts) == 0 {
+		return m
+	}
+	n = max(1, min(n, len(m.lineStarts)))
+	m.viewport.SetYOffset(m.lineStarts[n-1])
+	return m
+}
+
+// reveal lays the open file out and scrolls it as little as needed to show
+// the bytes from start to end of the text of the line numbered line+1
+func (m Model) reveal(line, start, end int) Model {
+	text := m.shownText()[line]
+	from := ansi.StringWidth(expandTabs(text[:start]))
+	to := ansi.StringWidth(expandTabs(text[:end]))
+	if m.noWrap && (from < m.xOffset || to > m.xOffset+m.textWidth) {
+		m.xOffset = max(0, from-m.textWidth/2)
+	}
+	m = m.layoutContent()
+
+	row := m.lineStarts[line]
+	if !m.noWrap {
+		row += from / m.textWidth
+	}
+	switch {
+	case row < m.viewport.YOffset:
+		m.viewport.SetYOffset(row)
+	case row >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(row - m.viewport.Height + 1)
+	}
+	return m
+}
+
+// toggleWrap switches between wrapping the long lines of the open file and
+// scrolling them sideways
+func (m Model) toggleWrap() Model {
+	m.noWrap = !m.noWrap
+	m.xOffset = 0
+	return m.layoutContent()
+}
+
+// scrollSideways scrolls unwrapped lines by columns, to the right when positive
+func (m Model) scrollSideways(columns int) Model {
+	if !m.noWrap {
+		return m
+	}
+	xOffset := max(0, min(m.xOffset+columns, m.maxXOffset))
+	if xOffset == m.xOffset {
+		return m
+	}
+	m.xOffset = xOffset
+	return m.layoutContent()
+}
+
+// openGotoLine opens the prompt for the number of the line to go to
+func (m Model) openGotoLine() Model {
+	m.jumping = true
+	m.finding = false
+	m.gotoLine = ""
+	return m
+}
+
+// updateGotoLine edits the line number typed in the go-to-line prompt and
+// scrolls to the line on Enter. It reports false for the keys handled as
+// usual, which scroll the file.
+func (m Model) updateGotoLine(msg tea.KeyMsg) (Model, bool) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.jumping = false
+	case tea.KeyEnter:
+		m.jumping = false
+		if n, err := strconv.Atoi(m.gotoLine); err == nil {
+			m = m.scrollToLine(n)
+		}
+	case tea.KeyBackspace:
+		if len(m.gotoLine) > 0 {
+			m.gotoLine = m.gotoLine[:len(m.gotoLine)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if r >= '0' && r <= '9' {
+				m.gotoLine += string(r)
+			}
+		}
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// positionView shows the line at the top of the viewport, the cursor of the
+// selection and the matches of the search, or the go-to-line prompt while it is open
+func (m Model) positionView() string {
+	if m.jumping {
+		return config.DetailStyle.Render("Go to line: ") + config.ValueStyle.Render(m.gotoLine+"▏")
+	}
+	return config.DetailStyle.Render(fmt.Sprintf("line %d of %d", m.topLine(), len(m.lineStarts))) + m.selectionView() + m.findView()
+}
+
+// textRange is a range of bytes of the text of a line, rendered in style
+type textRange struct {
+	start, end int
+	style      lipgloss.Style
+}
+
+// markRanges renders the ranges of the text of line in their style, ranges
+// being in order and apart from each other. The colors of line resume after
+// each range.
+func markRanges(line string, ranges []textRange) string {
+	if len(ranges) == 0 {
+		return line
+	}
+
+	// active are the escape sequences setting the colors since the last reset
+	var b, marked, active strings.Builder
+	j := 0
+	flush := func() {
+		if marked.Len() == 0 {
+			return
+		}
+		b.WriteString(ranges[j].style.Render(marked.String()))
+		b.WriteString(active.String())
+		marked.Reset()
+	}
+	var state byte
+	offset := 0
+	for len(line) > 0 {
+		seq, _, size, newState := ansi.DecodeSequence(line, state, nil)
+		state = newState
+		line = line[size:]
+		if isEscape(seq) {
+			if seq == "\x1b[0m" || seq == "\x1b[m" {
+				active.Reset()
+			} else {
+				active.WriteString(seq)
+			}
+			if marked.Len() == 0 {
+				b.WriteString(seq)
+			}
+			continue
+		}
+		for j < len(ranges) && offset >= ranges[j].end {
+			flush()
+			j++
+		}
+		if j < len(ranges) && offset >= ranges[j].start {
+			marked.WriteString(seq)
+		} else {
+			b.WriteString(seq)
+		}
+		offset += len(seq)
+	}
+	if j < len(ranges) {
+		flush()
+	}
+	return b.String()
+}
+
+// plainLines splits text into lines without their escape sequences
+func plainLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = plainText(line)
+	}
+	return lines
+}
+
+// plainText returns line without its escape sequences
+func plainText(line string) string {
+	var b strings.Builder
+	var state byte
+	for len(line) > 0 {
+		seq, _, size, newState := ansi.DecodeSequence(line, state, nil)
+		state = newState
+		line = line[size:]
+		if !isEscape(seq) {
+			b.WriteString(seq)
+		}
+	}
+	return b.String()
+}
+
+// isEscape reports whether seq, decoded by ansi.DecodeSequence, is an escape sequence
+func isEscape(seq string) bool {
+	return strings.HasPrefix(seq, "\x1b")
+}
+
+// expandTabs replaces the tabs of line with spaces, as many as lipgloss renders
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", strings.Repeat(" ", config.TabWidth))
+}
+
+// skipColumns drops the first n columns of the text of line, keeping its
+// escape sequences so that the rest of the line keeps its colors
+func skipColumns(line string, n int) string {
+	if n <= 0 {
+		return line
+	}
+	var b strings.Builder
+	var state byte
+	for len(line) > 0 {
+		seq, width, size, newState := ansi.DecodeSequence(line, state, nil)
+		state = newState
+		line = line[size:]
+		switch {
+		case width == 0:
+			b.WriteString(seq)
+		case n >= width:
+			n -= width
+		case n > 0:
+			// Replace the columns left of a wide character cut in half
+			b.WriteString(strings.Repeat(" ", width-n))
+			n = 0
+		default:
+			b.WriteString(seq)
+		}
+	}
+	return b.String()
+}
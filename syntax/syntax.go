@@ -0,0 +1,135 @@
+// Package syntax highlights source code for the terminal, detecting the
+// language from the file name, its extension or the shebang line.
+package syntax
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/muesli/termenv"
+)
+
+// interpreters maps the shebang interpreters chroma does not know by name onto lexers
+var interpreters = map[string]string{
+	"sh":   "bash",
+	"dash": "bash",
+	"ksh":  "bash",
+	"zsh":  "bash",
+	"node": "javascript",
+	"deno": "typescript",
+}
+
+// version matches the version suffix of interpreters, such as python3.12
+var version = regexp.MustCompile(`[0-9.]+$`)
+
+// Lexer returns the lexer of the file named name, found from its name and
+// extension, then from the shebang of content. It returns nil for plain text.
+func Lexer(name, content string) chroma.Lexer {
+	if lexer := lexers.Match(path.Base(name)); lexer != nil {
+		return lexer
+	}
+	interpreter := Interpreter(content)
+	if interpreter == "" {
+		return nil
+	}
+	if alias, ok := interpreters[interpreter]; ok {
+		interpreter = alias
+	}
+	return lexers.Get(interpreter)
+}
+
+// Interpreter returns the interpreter named by the shebang line of content,
+// without its version, or "" when content has no shebang
+func Interpreter(content string) string {
+	line, _, _ := strings.Cut(content, "\n")
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// Skip the options of env, such as -S
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = path.Base(field)
+				break
+			}
+		}
+	}
+	return version.ReplaceAllString(interpreter, "")
+}
+
+// Formatter returns the formatter for the colors of a terminal with profile,
+// or nil when the terminal has no colors
+func Formatter(profile termenv.Profile) chroma.Formatter {
+	switch profile {
+	case termenv.TrueColor:
+		return formatters.TTY16m
+	case termenv.ANSI256:
+		return formatters.TTY256
+	case termenv.ANSI:
+		return formatters.TTY16
+	default:
+		return nil
+	}
+}
+
+// Highlight renders the lines of content, the file named name, in the colors
+// of theme for a terminal with profile. It reports false when the language is
+// unknown or the terminal has no colors. Each line is rendered on its own, so
+// that lines can be shown apart from each other.
+func Highlight(name, content, theme string, profile termenv.Profile) ([]string, bool) {
+	return highlight(Lexer(name, content), content, theme, profile)
+}
+
+// HighlightCode renders the lines of content like Highlight, content being code
+// in language, such as the language of a fenced code block
+func HighlightCode(language, content, theme string, profile termenv.Profile) ([]string, bool) {
+	if language == "" {
+		return nil, false
+	}
+	return highlight(lexers.Get(language), content, theme, profile)
+}
+
+// highlight renders the lines of content with lexer
+func highlight(lexer chroma.Lexer, content, theme string, profile termenv.Profile) ([]string, bool) {
+	formatter := Formatter(profile)
+	if lexer == nil || formatter == nil {
+		return nil, false
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, content)
+	if err != nil {
+		return nil, false
+	}
+
+	style := styles.Get(theme)
+	plain := strings.Split(content, "\n")
+	lines := make([]string, 0, len(plain))
+	var b strings.Builder
+	for _, tokens := range chroma.SplitTokensIntoLines(iterator.Tokens()) {
+		if len(lines) == len(plain) {
+			break
+		}
+		last := &tokens[len(tokens)-1]
+		last.Value = strings.TrimSuffix(last.Value, "\n")
+		b.Reset()
+		if err := formatter.Format(&b, style, chroma.Literator(tokens...)); err != nil {
+			return nil, false
+		}
+		lines = append(lines, b.String())
+	}
+	// Lexers drop the empty last line of content ending with a newline
+	for len(lines) < len(plain) {
+		lines = append(lines, plain[len(lines)])
+	}
+	return lines, true
+}
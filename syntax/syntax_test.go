@@ -0,0 +1,60 @@
+package syntax
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+)
+
+// escapes matches the color escape sequences of terminal formatters
+var escapes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestLexer(t *testing.T) {
+	assert.Equal(t, "Go", Lexer("cmd/main.go", "package main").Config().Name)
+	assert.Equal(t, "Docker", Lexer("Dockerfile", "FROM alpine").Config().Name)
+	assert.Equal(t, "Python", Lexer("manage", "#!/usr/bin/env python3.12\nprint(1)\n").Config().Name)
+	assert.Equal(t, "Bash", Lexer("configure", "#!/bin/sh\necho hi\n").Config().Name)
+	assert.Equal(t, "JavaScript", Lexer("cli", "#!/usr/bin/env -S node --harmony\n").Config().Name)
+	assert.Nil(t, Lexer("NOTES", "Remember the milk"))
+}
+
+func TestInterpreter(t *testing.T) {
+	assert.Equal(t, "python", Interpreter("#!/usr/bin/env python3\n"))
+	assert.Equal(t, "ruby", Interpreter("#! /usr/local/bin/ruby -w"))
+	assert.Equal(t, "node", Interpreter("#!/usr/bin/env -S NODE_ENV=production node"))
+	assert.Equal(t, "", Interpreter("# Title\n#!/bin/sh"))
+}
+
+func TestHighlight(t *testing.T) {
+	content := "package main\n\n/* A comment\n   on two lines */\nfunc main() {}\n"
+	lines, ok := Highlight("main.go", content, "monokai", termenv.ANSI256)
+	assert.True(t, ok)
+
+	// Lines are rendered apart, keeping their text
+	plain := strings.Split(content, "\n")
+	if assert.Len(t, lines, len(plain)) {
+		for i, line := range lines {
+			assert.Equal(t, plain[i], escapes.ReplaceAllString(line, ""))
+		}
+	}
+	assert.Contains(t, lines[0], "\x1b[")
+	assert.True(t, strings.HasSuffix(lines[2], "\x1b[0m"))
+
+	lines, ok = Highlight("main.go", content, "monokai", termenv.TrueColor)
+	assert.True(t, ok)
+	assert.Contains(t, lines[0], "\x1b[38;2;")
+
+	_, ok = Highlight("main.go", content, "monokai", termenv.Ascii)
+	assert.False(t, ok)
+	_, ok = Highlight("NOTES", "Remember the milk", "monokai", termenv.TrueColor)
+	assert.False(t, ok)
+
+	lines, ok = HighlightCode("sh", "echo $HOME", "monokai", termenv.ANSI256)
+	assert.True(t, ok)
+	assert.Equal(t, "echo $HOME", escapes.ReplaceAllString(lines[0], ""))
+	_, ok = HighlightCode("", "echo $HOME", "monokai", termenv.ANSI256)
+	assert.False(t, ok)
+}